@@ -0,0 +1,132 @@
+package adapter_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/xerrors"
+	r "gopkg.in/rethinkdb/rethinkdb-go.v6"
+
+	"go.zenithar.org/pkg/db"
+	"go.zenithar.org/pkg/db/adapter/memory"
+	"go.zenithar.org/pkg/db/adapter/mongodb"
+	"go.zenithar.org/pkg/db/adapter/postgresql"
+	"go.zenithar.org/pkg/db/adapter/rethinkdb"
+	"go.zenithar.org/pkg/db/adapter/sqlite"
+	"go.zenithar.org/pkg/errors"
+)
+
+// counter counts records matching the given filter, restricted adapters only
+// allow filtering on 'id'.
+type counter func(ctx context.Context, restricted bool, filter interface{}) error
+
+// TestFilterableFields checks that all adapters accept the same criteria with
+// default options, and reject them with restricted ones. Backends not
+// available in tests must fail after criteria validation.
+func TestFilterableFields(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// SQL adapters filter on selected columns
+	columns := []string{"id", "name"}
+
+	pg, err := sqlx.Open("postgres", "postgres://localhost:1/test?sslmode=disable&connect_timeout=1")
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	defer pg.Close()
+
+	lite, err := sqlx.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	defer lite.Close()
+	lite.SetMaxOpenConns(1)
+	if _, err := lite.ExecContext(ctx, "CREATE TABLE users (id TEXT PRIMARY KEY, name TEXT)"); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+
+	mgo, err := mongo.NewClient(options.Client().ApplyURI("mongodb://localhost:1"))
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+
+	adapters := map[string]counter{
+		"memory": func(ctx context.Context, restricted bool, filter interface{}) error {
+			_, err := memory.NewCRUDTable("test", "users", memoryOpts(restricted)...).WhereCount(ctx, filter)
+			return err
+		},
+		"postgresql": func(ctx context.Context, restricted bool, filter interface{}) error {
+			_, err := postgresql.NewCRUDTable(pg, "test", "users", columns, nil, postgresqlOpts(restricted)...).WhereCount(ctx, filter)
+			return err
+		},
+		"sqlite": func(ctx context.Context, restricted bool, filter interface{}) error {
+			_, err := sqlite.NewCRUDTable(lite, "test", "users", columns, nil, sqliteOpts(restricted)...).WhereCount(ctx, filter)
+			return err
+		},
+		"mongodb": func(ctx context.Context, restricted bool, filter interface{}) error {
+			_, err := mongodb.NewCRUDTable(mgo, "test", "users", mongodbOpts(restricted)...).WhereCount(ctx, filter)
+			return err
+		},
+		"rethinkdb": func(ctx context.Context, restricted bool, filter interface{}) error {
+			_, err := rethinkdb.NewCRUDTable(&r.Session{}, "test", "users", rethinkdbOpts(restricted)...).WhereCount(ctx, filter)
+			return err
+		},
+	}
+
+	for name, count := range adapters {
+		name, count := name, count
+		t.Run(name, func(t *testing.T) {
+			filter := db.And(db.Eq("name", "bob"), db.IsNull("id"))
+
+			var e *errors.Error
+			if err := count(ctx, false, filter); xerrors.As(err, &e) && e.Code == errors.InvalidArgument {
+				t.Fatalf("criteria must be accepted by default, got %v", err)
+			}
+			if err := count(ctx, true, filter); !xerrors.As(err, &e) || e.Code != errors.InvalidArgument {
+				t.Fatalf("criteria must be rejected when restricted, got %v", err)
+			}
+		})
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func memoryOpts(restricted bool) []memory.Option {
+	if !restricted {
+		return nil
+	}
+	return []memory.Option{memory.WithFilterableFields("id")}
+}
+
+func postgresqlOpts(restricted bool) []postgresql.Option {
+	if !restricted {
+		return nil
+	}
+	return []postgresql.Option{postgresql.WithFilterableColumns("id")}
+}
+
+func sqliteOpts(restricted bool) []sqlite.Option {
+	if !restricted {
+		return nil
+	}
+	return []sqlite.Option{sqlite.WithFilterableColumns("id")}
+}
+
+func mongodbOpts(restricted bool) []mongodb.Option {
+	if !restricted {
+		return nil
+	}
+	return []mongodb.Option{mongodb.WithFilterableFields("id")}
+}
+
+func rethinkdbOpts(restricted bool) []rethinkdb.Option {
+	if !restricted {
+		return nil
+	}
+	return []rethinkdb.Option{rethinkdb.WithFilterableFields("id")}
+}
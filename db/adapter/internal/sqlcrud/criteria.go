@@ -0,0 +1,71 @@
+package sqlcrud
+
+import (
+	"fmt"
+
+	"go.zenithar.org/pkg/db"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// ConvertCriteria converts the given criterion to a sql filter, fields are
+// converted to snake case and must be part of filterable columns.
+func ConvertCriteria(c *db.Criterion, filterableColumns map[string]bool) (sq.Sqlizer, error) {
+	// Check criteria first
+	if err := c.Validate(func(field string) bool {
+		return filterableColumns[ToSnakeCase(field)]
+	}); err != nil {
+		return nil, err
+	}
+
+	return convertCriterion(c)
+}
+
+// -----------------------------------------------------------------------------
+
+func convertCriterion(c *db.Criterion) (sq.Sqlizer, error) {
+	column := ToSnakeCase(c.Field)
+
+	switch c.Operator {
+	case db.OpEq:
+		return sq.Eq{column: c.Values[0]}, nil
+	case db.OpNeq:
+		return sq.NotEq{column: c.Values[0]}, nil
+	case db.OpIn:
+		return sq.Eq{column: c.Values}, nil
+	case db.OpGt:
+		return sq.Gt{column: c.Values[0]}, nil
+	case db.OpGte:
+		return sq.GtOrEq{column: c.Values[0]}, nil
+	case db.OpLt:
+		return sq.Lt{column: c.Values[0]}, nil
+	case db.OpLte:
+		return sq.LtOrEq{column: c.Values[0]}, nil
+	case db.OpLike:
+		return sq.Like{column: c.Values[0]}, nil
+	case db.OpIsNull:
+		return sq.Eq{column: nil}, nil
+	case db.OpAnd, db.OpOr:
+		children := make([]sq.Sqlizer, 0, len(c.Children))
+		for _, child := range c.Children {
+			sub, err := convertCriterion(child)
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, sub)
+		}
+		if c.Operator == db.OpAnd {
+			return sq.And(children), nil
+		}
+		return sq.Or(children), nil
+	case db.OpNot:
+		sub, err := convertCriterion(c.Children[0])
+		if err != nil {
+			return nil, err
+		}
+		return sq.Expr("NOT (?)", sub), nil
+	default:
+	}
+
+	return nil, fmt.Errorf("sql: unsupported criteria operator '%d'", c.Operator)
+}
@@ -0,0 +1,80 @@
+package sqlcrud
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.zenithar.org/pkg/db"
+)
+
+func TestConvertCriteria(t *testing.T) {
+	filterable := map[string]bool{"name": true, "created_at": true}
+
+	testCases := []struct {
+		name     string
+		criteria *db.Criterion
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "equal",
+			criteria: db.Eq("name", "foo"),
+			wantSQL:  "name = ?",
+			wantArgs: []interface{}{"foo"},
+		},
+		{
+			name:     "in",
+			criteria: db.In("name", "foo", "bar"),
+			wantSQL:  "name IN (?,?)",
+			wantArgs: []interface{}{"foo", "bar"},
+		},
+		{
+			name:     "snake case range",
+			criteria: db.Between("createdAt", 1, 2),
+			wantSQL:  "(created_at >= ? AND created_at <= ?)",
+			wantArgs: []interface{}{1, 2},
+		},
+		{
+			name:     "composite",
+			criteria: db.Or(db.Like("name", "f%"), db.Not(db.IsNull("name"))),
+			wantSQL:  "(name LIKE ? OR NOT (name IS NULL))",
+			wantArgs: []interface{}{"f%"},
+		},
+		{
+			name:     "not filterable",
+			criteria: db.Eq("password", "secret"),
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ConvertCriteria(tt.criteria, filterable)
+			if tt.wantErr && err == nil {
+				t.Fatalf("expected error mst be raised")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("error must not be raised, got %v", err)
+			}
+			if err != nil {
+				return
+			}
+
+			sql, args, err := got.ToSql()
+			if err != nil {
+				t.Fatalf("error must not be raised, got %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Fatalf("got %q, wanted %q", sql, tt.wantSQL)
+			}
+			if !cmp.Equal(args, tt.wantArgs) {
+				t.Fatalf("got %v, wanted %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
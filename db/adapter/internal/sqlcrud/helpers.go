@@ -1,4 +1,4 @@
-package postgresql
+package sqlcrud
 
 import (
 	"fmt"
@@ -23,14 +23,15 @@ func ToSnakeCase(in string) string {
 	return string(out)
 }
 
-// ConvertSortParameters to sql query string
+// ConvertSortParameters to sql query string, sort order is preserved and
+// columns not part of sortable ones are ignored.
 func ConvertSortParameters(params db.SortParameters, sortableColumns map[string]bool) []string {
 
 	var sorts []string
-	for column, direction := range params {
-		realColumn := ToSnakeCase(column)
+	for _, f := range params {
+		realColumn := ToSnakeCase(f.Field)
 		if _, ok := sortableColumns[realColumn]; ok {
-			switch direction {
+			switch f.Direction {
 			case db.Ascending:
 				sorts = append(sorts, fmt.Sprintf("%s asc", realColumn))
 			case db.Descending:
@@ -42,3 +43,13 @@ func ConvertSortParameters(params db.SortParameters, sortableColumns map[string]
 
 	return sorts
 }
+
+// -----------------------------------------------------------------------------
+
+func toSet(values []string) map[string]bool {
+	res := map[string]bool{}
+	for _, v := range values {
+		res[v] = true
+	}
+	return res
+}
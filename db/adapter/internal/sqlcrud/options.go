@@ -0,0 +1,73 @@
+package sqlcrud
+
+import "context"
+
+// Option defines table option builder.
+type Option func(*Table)
+
+// WithFilterableColumns sets the columns allowed in backend-neutral criteria,
+// all selected columns are allowed by default.
+func WithFilterableColumns(columns ...string) Option {
+	return func(t *Table) {
+		t.filterableColumns = toSet(columns)
+	}
+}
+
+// WithPrimaryKey sets the primary key column used as keyset pagination
+// tie-breaker, 'id' by default.
+func WithPrimaryKey(column string) Option {
+	return func(t *Table) {
+		t.primaryKey = column
+	}
+}
+
+// WithVersionColumn enables optimistic concurrency control using the given
+// column, updates must contain the expected version which is incremented.
+func WithVersionColumn(column string) Option {
+	return func(t *Table) {
+		t.versionColumn = column
+	}
+}
+
+// WithTimestamps sets the columns automatically set to the current time on
+// creation and update.
+func WithTimestamps(createdAt, updatedAt string) Option {
+	return func(t *Table) {
+		t.tracking.CreatedAt = createdAt
+		t.tracking.UpdatedAt = updatedAt
+	}
+}
+
+// WithSoftDelete marks removed rows by setting the given timestamp column
+// instead of deleting them, marked rows are excluded from all queries.
+func WithSoftDelete(deletedAt string) Option {
+	return func(t *Table) {
+		t.tracking.DeletedAt = deletedAt
+	}
+}
+
+// WithAudit sets the columns recording the acting principal from context on
+// creation and update.
+func WithAudit(createdBy, updatedBy string) Option {
+	return func(t *Table) {
+		t.tracking.CreatedBy = createdBy
+		t.tracking.UpdatedBy = updatedBy
+	}
+}
+
+// WithExecutors sets the functions returning the executor used for writes and
+// reads, e.g. a transaction held by the context or a replica.
+func WithExecutors(writer, reader func(context.Context) Executor) Option {
+	return func(t *Table) {
+		t.writer = writer
+		t.reader = reader
+	}
+}
+
+// WithPrimary sets the function returning a context forcing reads to the
+// primary, used to check version conflicts after writes.
+func WithPrimary(primary func(context.Context) context.Context) Option {
+	return func(t *Table) {
+		t.primary = primary
+	}
+}
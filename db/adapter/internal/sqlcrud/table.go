@@ -0,0 +1,649 @@
+// Package sqlcrud provides the CRUD table implementation shared by SQL
+// database adapters, dialect specific behaviors are given by adapters.
+package sqlcrud
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"go.zenithar.org/pkg/db"
+	"go.zenithar.org/pkg/errors"
+	"go.zenithar.org/pkg/log"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/reflectx"
+)
+
+var _ db.Repository = (*Table)(nil)
+
+// Executor describes a session or a transaction used to run statements.
+type Executor interface {
+	sqlx.ExtContext
+	PreparexContext(ctx context.Context, query string) (*sqlx.Stmt, error)
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+// Dialect describes the database specific behaviors.
+type Dialect struct {
+	// Name is used as error message prefix.
+	Name string
+	// Placeholder is the bind parameter format.
+	Placeholder sq.PlaceholderFormat
+	// WrapError wraps and classifies driver errors.
+	WrapError func(err error, format string, args ...interface{}) error
+}
+
+// Table contains the basic implementation of the SQL interface
+type Table struct {
+	dialect Dialect
+	table   string
+	db      string
+	session *sqlx.DB
+
+	mapper            *reflectx.Mapper
+	columns           []string
+	sortableColumns   map[string]bool
+	filterableColumns map[string]bool
+	primaryKey        string
+	versionColumn     string
+	tracking          db.Tracking
+
+	writer  func(context.Context) Executor
+	reader  func(context.Context) Executor
+	primary func(context.Context) context.Context
+}
+
+// New sets up a new Table struct, statements are executed by the session
+// unless overridden by options.
+func New(dialect Dialect, session *sqlx.DB, db, table string, columns, sortable []string, opts ...Option) *Table {
+	t := &Table{
+		dialect:           dialect,
+		db:                db,
+		table:             table,
+		session:           session,
+		mapper:            reflectx.NewMapper("db"),
+		columns:           columns,
+		sortableColumns:   toSet(sortable),
+		filterableColumns: toSet(columns),
+		primaryKey:        "id",
+		writer:            func(context.Context) Executor { return session },
+		reader:            func(context.Context) Executor { return session },
+		primary:           func(ctx context.Context) context.Context { return ctx },
+	}
+
+	// Apply options
+	for _, o := range opts {
+		o(t)
+	}
+
+	return t
+}
+
+// -----------------------------------------------------------------------------
+
+// GetTableName returns table's name
+func (t *Table) GetTableName() string {
+	return t.table
+}
+
+// GetDBName returns database's name
+func (t *Table) GetDBName() string {
+	return t.db
+}
+
+// GetSession returns the current session
+func (t *Table) GetSession() interface{} {
+	return t.session
+}
+
+// Columns returns the selected columns.
+func (t *Table) Columns() []string {
+	return t.columns
+}
+
+// Tracking returns the automatically maintained columns.
+func (t *Table) Tracking() db.Tracking {
+	return t.tracking
+}
+
+// -----------------------------------------------------------------------------
+
+// Create a record
+func (t *Table) Create(ctx context.Context, data interface{}) error {
+
+	// Extract columns and values
+	columns, values := t.ExtractColumnPairs(data)
+	columns, values = MergeColumnPairs(columns, values, t.tracking.OnCreate(ctx, time.Now().UTC()))
+
+	// Prepare query
+	query := sq.Insert(t.table).
+		Columns(columns...).
+		Values(values...).
+		PlaceholderFormat(t.dialect.Placeholder)
+
+	// Build sql query
+	q, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: unable to build query: %w", t.dialect.Name, err)
+	}
+
+	// Prepare the statement
+	stmt, err := t.writer(ctx).PreparexContext(ctx, q)
+	if err != nil {
+		return t.dialect.WrapError(err, "%s: unable to prepare query", t.dialect.Name)
+	}
+	defer func(stmt *sqlx.Stmt) {
+		log.SafeClose(stmt, "Unable to close statement")
+	}(stmt)
+
+	// Do the insert query
+	_, err = stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return t.dialect.WrapError(err, "%s: unable to execute query", t.dialect.Name)
+	}
+
+	return nil
+}
+
+// WhereCount is used to cound resultset elements from the given filter
+func (t *Table) WhereCount(ctx context.Context, filter interface{}) (int, error) {
+	// Prepare query
+	qb := sq.Select("COUNT(*) as count").
+		From(t.table).
+		PlaceholderFormat(t.dialect.Placeholder)
+
+	where, err := t.Where(filter)
+	if err != nil {
+		return 0, err
+	}
+	qb = qb.Where(where)
+
+	// Build sql query
+	q, args, err := qb.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("%s: unable to build query: %w", t.dialect.Name, err)
+	}
+
+	// Prepare the statement
+	stmt, err := t.reader(ctx).PreparexContext(ctx, q)
+	if err != nil {
+		return 0, t.dialect.WrapError(err, "%s: unable to prepare query", t.dialect.Name)
+	}
+	defer func(stmt *sqlx.Stmt) {
+		log.SafeClose(stmt, "Unable to close statement")
+	}(stmt)
+
+	var count int
+	if err := stmt.QueryRowContext(ctx, args...).Scan(&count); err == sql.ErrNoRows {
+		return 0, db.ErrNoResult
+	} else if err != nil {
+		return 0, t.dialect.WrapError(err, "%s: unable to execute query", t.dialect.Name)
+	}
+
+	// Return no error
+	return count, nil
+}
+
+// WhereAndFetchOne returns only one element from the given filter
+func (t *Table) WhereAndFetchOne(ctx context.Context, filter interface{}, result interface{}) error {
+	where, err := t.Where(filter)
+	if err != nil {
+		return err
+	}
+
+	// Prepare query
+	qb := sq.Select(t.columns...).
+		From(t.table).
+		Where(where).
+		Limit(1).
+		PlaceholderFormat(t.dialect.Placeholder)
+
+	// Build sql query
+	q, args, err := qb.ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: unable to build query: %w", t.dialect.Name, err)
+	}
+
+	// Prepare the statement
+	stmt, err := t.reader(ctx).PreparexContext(ctx, q)
+	if err != nil {
+		return t.dialect.WrapError(err, "%s: unable to prepare query", t.dialect.Name)
+	}
+	defer func(stmt *sqlx.Stmt) {
+		log.SafeClose(stmt, "Unable to close statement")
+	}(stmt)
+
+	// Do the select query
+	err = stmt.QueryRowxContext(ctx, args...).StructScan(result)
+	if err == sql.ErrNoRows {
+		return db.ErrNoResult
+	} else if err != nil {
+		return t.dialect.WrapError(err, "%s: unable to execute query", t.dialect.Name)
+	}
+
+	// Return no error
+	return nil
+}
+
+// Update the collection element with updates set matching the given filter
+func (t *Table) Update(ctx context.Context, updates map[string]interface{}, filter interface{}) error {
+	where, err := t.Where(filter)
+	if err != nil {
+		return err
+	}
+
+	// Prepare query
+	qb := sq.Update(t.table).
+		Where(where).
+		PlaceholderFormat(t.dialect.Placeholder)
+
+	// Check and increment version
+	var expected interface{}
+	if t.versionColumn != "" {
+		expected, updates, err = db.ExtractVersion(updates, t.versionColumn)
+		if err != nil {
+			return err
+		}
+		updates[t.versionColumn] = sq.Expr(fmt.Sprintf("%s + 1", t.versionColumn))
+		qb = qb.Where(sq.Eq{t.versionColumn: expected})
+	}
+
+	// Apply tracking columns
+	values := make(map[string]interface{}, len(updates))
+	for column, value := range updates {
+		values[column] = value
+	}
+	for column, value := range t.tracking.OnUpdate(ctx, time.Now().UTC()) {
+		values[column] = value
+	}
+	qb = qb.SetMap(values)
+
+	// Build sql query
+	q, args, err := qb.ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: unable to build query: %w", t.dialect.Name, err)
+	}
+
+	// Prepare the statement
+	stmt, err := t.writer(ctx).PreparexContext(ctx, q)
+	if err != nil {
+		return t.dialect.WrapError(err, "%s: unable to prepare query", t.dialect.Name)
+	}
+	defer func(stmt *sqlx.Stmt) {
+		log.SafeClose(stmt, "Unable to close statement")
+	}(stmt)
+
+	// Do the update query
+	res, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return t.dialect.WrapError(err, "%s: unable to execute query", t.dialect.Name)
+	}
+
+	// Check updates
+	count, err := res.RowsAffected()
+	if err != nil {
+		return t.dialect.WrapError(err, "%s: unable to retrieve query result", t.dialect.Name)
+	}
+
+	// If no rows where affected return an handled error
+	if count == 0 {
+		return t.noModification(ctx, filter, expected)
+	}
+
+	// Return no error
+	return nil
+}
+
+// RemoveOne is used to remove one element from the collection that match the filter
+func (t *Table) RemoveOne(ctx context.Context, filter interface{}) error {
+	where, err := t.Where(filter)
+	if err != nil {
+		return err
+	}
+
+	// Prepare query
+	var qb sq.Sqlizer = sq.Delete(t.table).
+		Where(where).
+		PlaceholderFormat(t.dialect.Placeholder)
+
+	// Mark as deleted
+	if t.tracking.SoftDelete() {
+		qb = sq.Update(t.table).
+			SetMap(t.tracking.OnDelete(ctx, time.Now().UTC())).
+			Where(where).
+			PlaceholderFormat(t.dialect.Placeholder)
+	}
+
+	// Build sql query
+	q, args, err := qb.ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: unable to build query: %w", t.dialect.Name, err)
+	}
+
+	// Prepare the statement
+	stmt, err := t.writer(ctx).PreparexContext(ctx, q)
+	if err != nil {
+		return t.dialect.WrapError(err, "%s: unable to prepare query", t.dialect.Name)
+	}
+	defer func(stmt *sqlx.Stmt) {
+		log.SafeClose(stmt, "Unable to close statement")
+	}(stmt)
+
+	// Do the delete query
+	res, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return t.dialect.WrapError(err, "%s: unable to execute query", t.dialect.Name)
+	}
+
+	// Check updates
+	count, err := res.RowsAffected()
+	if err != nil {
+		return t.dialect.WrapError(err, "%s: unable to retrieve query result", t.dialect.Name)
+	}
+
+	// If no rows where affected return an handled error
+	if count == 0 {
+		return db.ErrNoModification
+	}
+
+	// Return no error
+	return nil
+}
+
+// Search for element in collection
+func (t *Table) Search(ctx context.Context, filter interface{}, pagination *db.Pagination, sortParams *db.SortParameters, results interface{}) (int, error) {
+	// Check sort parameters
+	sorts, err := t.Sorts(sortParams)
+	if err != nil {
+		return 0, err
+	}
+
+	// Initialize statement
+	q := sq.Select(t.columns...).
+		From(t.table).
+		PlaceholderFormat(t.dialect.Placeholder)
+
+	where, err := t.Where(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	// Prepare the query
+	q = q.Where(where)
+
+	// Keyset pagination, total count is not computed
+	if pagination != nil && pagination.IsKeyset() {
+		return t.searchAfter(ctx, q, filter, pagination, sorts, results)
+	}
+
+	// Count result set first
+	count, err := t.WhereCount(ctx, filter)
+	if err != nil {
+		return 0, t.dialect.WrapError(err, "%s: unable to retrieve collection count", t.dialect.Name)
+	}
+
+	// If no result skip data request
+	if count == 0 {
+		return 0, db.ErrNoResult
+	}
+
+	if pagination != nil {
+		pagination.SetTotal(uint(count))
+	}
+
+	// Apply pagination on data query only
+	if pagination != nil {
+		q = q.Offset(uint64(pagination.Offset())).Limit(uint64(pagination.PerPage))
+	}
+
+	// Apply sort parameters
+	q = q.OrderBy(t.OrderBy(sorts)...)
+
+	// Do the query
+	sqlData, args, err := q.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("%s: unable to build query: %w", t.dialect.Name, err)
+	}
+
+	// Prepare the statement
+	stmt, err := t.reader(ctx).PreparexContext(ctx, sqlData)
+	if err != nil {
+		return 0, t.dialect.WrapError(err, "%s: unable to prepare query", t.dialect.Name)
+	}
+	defer func(stmt *sqlx.Stmt) {
+		log.SafeClose(stmt, "Unable to close statement")
+	}(stmt)
+
+	if err := stmt.SelectContext(ctx, results, args...); err == sql.ErrNoRows {
+		return 0, db.ErrNoResult
+	} else if err != nil {
+		return 0, t.dialect.WrapError(err, "%s: unable to execute query", t.dialect.Name)
+	}
+
+	// Return no error
+	return count, nil
+}
+
+// -----------------------------------------------------------------------------
+
+// Sorts checks the given sort parameters against sortable columns, and adds
+// the primary key as tie-breaker.
+func (t *Table) Sorts(params *db.SortParameters) (db.SortParameters, error) {
+	if params == nil {
+		params = &db.SortParameters{}
+	}
+
+	// Check sortable columns
+	if err := params.Validate(func(field string) bool {
+		column := ToSnakeCase(field)
+		return column == t.primaryKey || t.sortableColumns[column]
+	}); err != nil {
+		return nil, err
+	}
+
+	return params.TieBreak(t.primaryKey), nil
+}
+
+// OrderBy converts checked sort parameters to sql order clauses, including the
+// primary key tie-breaker.
+func (t *Table) OrderBy(sorts db.SortParameters) []string {
+	sortable := map[string]bool{t.primaryKey: true}
+	for column := range t.sortableColumns {
+		sortable[column] = true
+	}
+	return ConvertSortParameters(sorts, sortable)
+}
+
+// Where converts backend-neutral criteria to sql filter, other supported
+// filters are used as is. Deleted rows are excluded when soft delete is
+// enabled.
+func (t *Table) Where(filter interface{}) (interface{}, error) {
+	if c, ok := filter.(*db.Criterion); ok {
+		var err error
+		if filter, err = ConvertCriteria(c, t.filterableColumns); err != nil {
+			return nil, err
+		}
+	}
+
+	// Exclude deleted rows
+	if !t.tracking.SoftDelete() {
+		switch filter.(type) {
+		case nil, sq.Sqlizer, map[string]interface{}, string:
+			return filter, nil
+		default:
+		}
+		return nil, errors.Newf(errors.InvalidArgument, nil, "%s: unsupported filter type '%T'", t.dialect.Name, filter)
+	}
+
+	notDeleted := sq.Eq{t.tracking.DeletedAt: nil}
+	switch f := filter.(type) {
+	case nil:
+		return notDeleted, nil
+	case sq.Sqlizer:
+		return sq.And{f, notDeleted}, nil
+	case map[string]interface{}:
+		return sq.And{sq.Eq(f), notDeleted}, nil
+	case string:
+		return sq.And{sq.Expr(f), notDeleted}, nil
+	default:
+	}
+
+	return nil, errors.Newf(errors.InvalidArgument, nil, "%s: unsupported filter type '%T'", t.dialect.Name, filter)
+}
+
+// ExtractColumnPairs returns the columns and values of the given element, in
+// column name order.
+func (t *Table) ExtractColumnPairs(data interface{}) ([]string, []interface{}) {
+	// Create type mapper
+	valueMap := t.mapper.FieldMap(reflect.ValueOf(data))
+
+	// Extract columns in a stable order
+	columns := make([]string, 0, len(valueMap))
+	for column := range valueMap {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	values := make([]interface{}, len(columns))
+	for i, column := range columns {
+		values[i] = valueMap[column].Interface()
+	}
+
+	// Return all elements
+	return columns, values
+}
+
+// -----------------------------------------------------------------------------
+
+// searchAfter executes the query using keyset pagination, one more element is
+// fetched to detect the next page. It returns the page element count.
+func (t *Table) searchAfter(ctx context.Context, q sq.SelectBuilder, filter interface{}, pagination *db.Pagination, sorts db.SortParameters, results interface{}) (int, error) {
+	// Use column names
+	fields := make([]db.SortField, len(sorts))
+	for i, f := range sorts {
+		fields[i] = db.SortField{Field: ToSnakeCase(f.Field), Direction: f.Direction}
+	}
+
+	// Check cursor origin
+	if err := pagination.Bind(t.table, filter, fields); err != nil {
+		return 0, err
+	}
+
+	// Start after cursor position
+	if after := pagination.After(); len(after) > 0 {
+		c, err := db.KeysetCriterion(fields, after)
+		if err != nil {
+			return 0, err
+		}
+		where, err := convertCriterion(c)
+		if err != nil {
+			return 0, err
+		}
+		q = q.Where(where)
+	}
+
+	// Apply sort parameters
+	for _, f := range fields {
+		q = q.OrderBy(fmt.Sprintf("%s %s", f.Field, f.Direction))
+	}
+	q = q.Limit(uint64(pagination.PerPage) + 1)
+
+	// Do the query
+	sqlData, args, err := q.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("%s: unable to build query: %w", t.dialect.Name, err)
+	}
+
+	// Prepare the statement
+	stmt, err := t.reader(ctx).PreparexContext(ctx, sqlData)
+	if err != nil {
+		return 0, t.dialect.WrapError(err, "%s: unable to prepare query", t.dialect.Name)
+	}
+	defer func(stmt *sqlx.Stmt) {
+		log.SafeClose(stmt, "Unable to close statement")
+	}(stmt)
+
+	if err := stmt.SelectContext(ctx, results, args...); err == sql.ErrNoRows {
+		return 0, db.ErrNoResult
+	} else if err != nil {
+		return 0, t.dialect.WrapError(err, "%s: unable to execute query", t.dialect.Name)
+	}
+
+	// Truncate extra element
+	items := reflect.Indirect(reflect.ValueOf(results))
+	hasNext := items.Len() > int(pagination.PerPage)
+	if hasNext {
+		items.Set(items.Slice(0, int(pagination.PerPage)))
+	}
+	if items.Len() == 0 {
+		if len(pagination.After()) == 0 {
+			return 0, db.ErrNoResult
+		}
+		return 0, pagination.SetNext(nil, false)
+	}
+
+	// Extract last element sort key values
+	last := reflect.Indirect(items.Index(items.Len() - 1))
+	tm := t.mapper.TypeMap(last.Type())
+	values := make([]interface{}, len(fields))
+	for i, f := range fields {
+		fi, ok := tm.Names[f.Field]
+		if !ok {
+			return 0, fmt.Errorf("%s: unable to extract sort key '%s'", t.dialect.Name, f.Field)
+		}
+		values[i] = reflectx.FieldByIndexesReadOnly(last, fi.Index).Interface()
+	}
+
+	return items.Len(), pagination.SetNext(values, hasNext)
+}
+
+// noModification returns the error raised when an update affected nothing, a
+// version conflict is raised if the filter matches without expected version.
+func (t *Table) noModification(ctx context.Context, filter, expected interface{}) error {
+	if t.versionColumn == "" {
+		return db.ErrNoModification
+	}
+
+	count, err := t.WhereCount(t.primary(ctx), filter)
+	if err != nil {
+		return t.dialect.WrapError(err, "%s: unable to check version conflict", t.dialect.Name)
+	}
+	if count > 0 {
+		return db.VersionConflict(t.table, expected)
+	}
+
+	return db.ErrNoModification
+}
+
+// MergeColumnPairs sets the given values, overriding existing columns.
+func MergeColumnPairs(columns []string, values []interface{}, extra map[string]interface{}) ([]string, []interface{}) {
+	// Sort extra columns to keep a stable column order
+	names := make([]string, 0, len(extra))
+	for column := range extra {
+		names = append(names, column)
+	}
+	sort.Strings(names)
+
+	for _, column := range names {
+		found := false
+		for i := range columns {
+			if columns[i] == column {
+				values[i] = extra[column]
+				found = true
+				break
+			}
+		}
+		if !found {
+			columns = append(columns, column)
+			values = append(values, extra[column])
+		}
+	}
+
+	return columns, values
+}
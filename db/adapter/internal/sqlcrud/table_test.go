@@ -0,0 +1,119 @@
+package sqlcrud
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.zenithar.org/pkg/db"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+var testDialect = Dialect{
+	Name:        "test",
+	Placeholder: sq.Question,
+	WrapError: func(err error, format string, args ...interface{}) error {
+		return fmt.Errorf("%s: %w", fmt.Sprintf(format, args...), err)
+	},
+}
+
+func TestTable_Where(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		opts     []Option
+		filter   interface{}
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name:    "no filter",
+			wantSQL: "SELECT * FROM users",
+		},
+		{
+			name:     "criteria",
+			filter:   db.Eq("name", "foo"),
+			wantSQL:  "SELECT * FROM users WHERE name = ?",
+			wantArgs: []interface{}{"foo"},
+		},
+		{
+			name:    "soft delete without filter",
+			opts:    []Option{WithSoftDelete("deleted_at")},
+			wantSQL: "SELECT * FROM users WHERE deleted_at IS NULL",
+		},
+		{
+			name:     "soft delete with criteria",
+			opts:     []Option{WithSoftDelete("deleted_at")},
+			filter:   db.Eq("name", "foo"),
+			wantSQL:  "SELECT * FROM users WHERE (name = ? AND deleted_at IS NULL)",
+			wantArgs: []interface{}{"foo"},
+		},
+		{
+			name:     "soft delete with map",
+			opts:     []Option{WithSoftDelete("deleted_at")},
+			filter:   map[string]interface{}{"id": "123"},
+			wantSQL:  "SELECT * FROM users WHERE (id = ? AND deleted_at IS NULL)",
+			wantArgs: []interface{}{"123"},
+		},
+		{
+			name:    "unsupported filter",
+			filter:  42,
+			wantErr: true,
+		},
+		{
+			name:    "soft delete with unsupported filter",
+			opts:    []Option{WithSoftDelete("deleted_at")},
+			filter:  42,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			underTest := New(testDialect, nil, "db", "users", []string{"id", "name"}, nil, tt.opts...)
+
+			where, err := underTest.Where(tt.filter)
+			if tt.wantErr && err == nil {
+				t.Fatalf("expected error mst be raised")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("error must not be raised, got %v", err)
+			}
+			if err != nil {
+				return
+			}
+
+			q, args, err := sq.Select("*").From("users").Where(where).ToSql()
+			if err != nil {
+				t.Fatalf("error must not be raised, got %v", err)
+			}
+			if q != tt.wantSQL {
+				t.Fatalf("got %q, wanted %q", q, tt.wantSQL)
+			}
+			if !cmp.Equal(args, tt.wantArgs) {
+				t.Fatalf("got %v, wanted %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestMergeColumnPairs(t *testing.T) {
+	columns, values := MergeColumnPairs(
+		[]string{"id", "updated_at"},
+		[]interface{}{"123", "old"},
+		map[string]interface{}{"updated_at": "now", "created_at": "now"},
+	)
+
+	if want := []string{"id", "updated_at", "created_at"}; !cmp.Equal(columns, want) {
+		t.Fatalf("got %v, wanted %v", columns, want)
+	}
+	if want := []interface{}{"123", "now", "now"}; !cmp.Equal(values, want) {
+		t.Fatalf("got %v, wanted %v", values, want)
+	}
+}
@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+
+	"go.zenithar.org/pkg/db"
+)
+
+// Predicate is a filter expressed as a Go function, it receives a copy of each
+// stored record.
+type Predicate func(record interface{}) bool
+
+// -----------------------------------------------------------------------------
+
+// matcher evaluates a filter against a stored record.
+type matcher func(record reflect.Value) bool
+
+func (d *Default) matchCriterion(c *db.Criterion) (matcher, error) {
+	switch c.Operator {
+	case db.OpEq, db.OpNeq, db.OpGt, db.OpGte, db.OpLt, db.OpLte:
+		op, value := c.Operator, c.Values[0]
+		return func(record reflect.Value) bool {
+			res, ok := compare(d.field(record, c.Field), value)
+			if !ok {
+				return op == db.OpNeq
+			}
+			switch op {
+			case db.OpEq:
+				return res == 0
+			case db.OpNeq:
+				return res != 0
+			case db.OpGt:
+				return res > 0
+			case db.OpGte:
+				return res >= 0
+			case db.OpLt:
+				return res < 0
+			default:
+				return res <= 0
+			}
+		}, nil
+	case db.OpIn:
+		values := c.Values
+		return func(record reflect.Value) bool {
+			current := d.field(record, c.Field)
+			for _, v := range values {
+				if res, ok := compare(current, v); ok && res == 0 {
+					return true
+				}
+			}
+			return false
+		}, nil
+	case db.OpLike:
+		re, err := regexp.Compile(db.LikeToRegexp(c.Values[0].(string)))
+		if err != nil {
+			return nil, fmt.Errorf("memory: invalid like pattern: %w", err)
+		}
+		return func(record reflect.Value) bool {
+			s, ok := d.field(record, c.Field).(string)
+			return ok && re.MatchString(s)
+		}, nil
+	case db.OpIsNull:
+		return func(record reflect.Value) bool {
+			return isNull(d.field(record, c.Field))
+		}, nil
+	case db.OpAnd, db.OpOr:
+		children := make([]matcher, 0, len(c.Children))
+		for _, child := range c.Children {
+			sub, err := d.matchCriterion(child)
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, sub)
+		}
+		all := c.Operator == db.OpAnd
+		return func(record reflect.Value) bool {
+			for _, sub := range children {
+				if sub(record) != all {
+					return !all
+				}
+			}
+			return all
+		}, nil
+	case db.OpNot:
+		sub, err := d.matchCriterion(c.Children[0])
+		if err != nil {
+			return nil, err
+		}
+		return func(record reflect.Value) bool {
+			return !sub(record)
+		}, nil
+	default:
+	}
+
+	return nil, fmt.Errorf("memory: unsupported criteria operator '%d'", c.Operator)
+}
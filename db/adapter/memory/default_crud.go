@@ -0,0 +1,651 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"go.zenithar.org/pkg/db"
+	"go.zenithar.org/pkg/errors"
+
+	"github.com/jmoiron/sqlx/reflectx"
+)
+
+var _ db.Repository = (*Default)(nil)
+
+// Default contains a thread-safe in-memory implementation of the CRUD
+// interface, records are deep copies of created structs keyed by their
+// primary key.
+type Default struct {
+	sync.RWMutex
+
+	table string
+	db    string
+
+	tagName          string
+	mapper           *reflectx.Mapper
+	filterableFields map[string]bool
+	sortableFields   map[string]bool
+	primaryKey       string
+	versionField     string
+	tracking         db.Tracking
+
+	recordType reflect.Type
+	records    map[interface{}]reflect.Value
+}
+
+// NewCRUDTable sets up a new Default struct
+func NewCRUDTable(db, table string, opts ...Option) *Default {
+	d := &Default{
+		db:               db,
+		table:            table,
+		tagName:          "db",
+		filterableFields: map[string]bool{},
+		sortableFields:   map[string]bool{},
+		primaryKey:       "id",
+		records:          map[interface{}]reflect.Value{},
+	}
+
+	// Apply options
+	for _, o := range opts {
+		o(d)
+	}
+
+	d.mapper = reflectx.NewMapper(d.tagName)
+
+	return d
+}
+
+// -----------------------------------------------------------------------------
+
+// GetTableName returns table's name
+func (d *Default) GetTableName() string {
+	return d.table
+}
+
+// GetDBName returns database's name
+func (d *Default) GetDBName() string {
+	return d.db
+}
+
+// GetSession returns the current session
+func (d *Default) GetSession() interface{} {
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+// Create a record
+func (d *Default) Create(ctx context.Context, data interface{}) error {
+	d.Lock()
+	defer d.Unlock()
+
+	// Check record type
+	record := reflect.Indirect(reflect.ValueOf(data))
+	if record.Kind() != reflect.Struct {
+		return errors.Newf(errors.InvalidArgument, nil, "memory: record must be a struct, got '%T'", data)
+	}
+	if d.recordType == nil {
+		d.recordType = record.Type()
+	}
+	if record.Type() != d.recordType {
+		return errors.Newf(errors.InvalidArgument, nil, "memory: record must be a '%s', got '%T'", d.recordType, data)
+	}
+
+	// Extract primary key
+	id, err := d.id(record)
+	if err != nil {
+		return err
+	}
+	if _, ok := d.records[id]; ok {
+		return errors.Newf(errors.AlreadyExists, nil, "memory: record '%v' already exists", id)
+	}
+
+	// Apply tracking fields
+	record = deepCopy(record)
+	for field, value := range d.tracking.OnCreate(ctx, time.Now().UTC()) {
+		if err := d.set(record, field, value); err != nil {
+			return err
+		}
+	}
+
+	d.records[id] = record
+
+	return nil
+}
+
+// WhereCount is used to cound resultset elements from the given filter
+func (d *Default) WhereCount(ctx context.Context, filter interface{}) (int, error) {
+	d.RLock()
+	defer d.RUnlock()
+
+	records, err := d.find(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	// Return no error
+	return len(records), nil
+}
+
+// WhereAndFetchOne returns only one element from the given filter
+func (d *Default) WhereAndFetchOne(ctx context.Context, filter interface{}, result interface{}) error {
+	d.RLock()
+	defer d.RUnlock()
+
+	records, err := d.find(filter)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return db.ErrNoResult
+	}
+
+	// Copy record to result
+	dest := reflect.ValueOf(result)
+	if dest.Kind() != reflect.Ptr || dest.IsNil() || dest.Elem().Type() != d.recordType {
+		return errors.Newf(errors.InvalidArgument, nil, "memory: result must be a '*%s', got '%T'", d.recordType, result)
+	}
+	dest.Elem().Set(deepCopy(records[0]))
+
+	// Return no error
+	return nil
+}
+
+// Update the collection element with updates set matching the given filter
+func (d *Default) Update(ctx context.Context, updates map[string]interface{}, filter interface{}) error {
+	d.Lock()
+	defer d.Unlock()
+
+	// Extract expected version
+	var (
+		expected interface{}
+		err      error
+	)
+	if d.versionField != "" {
+		expected, updates, err = db.ExtractVersion(updates, d.versionField)
+		if err != nil {
+			return err
+		}
+	}
+
+	records, err := d.find(filter)
+	if err != nil {
+		return err
+	}
+
+	// If no records where affected return an handled error
+	if len(records) == 0 {
+		return db.ErrNoModification
+	}
+
+	// Check version of matching records
+	if d.versionField != "" {
+		matching := make([]reflect.Value, 0, len(records))
+		for _, record := range records {
+			if res, ok := compare(d.field(record, d.versionField), expected); ok && res == 0 {
+				matching = append(matching, record)
+			}
+		}
+		if len(matching) == 0 {
+			return db.VersionConflict(d.table, expected)
+		}
+		records = matching
+	}
+	tracking := d.tracking.OnUpdate(ctx, time.Now().UTC())
+
+	// Apply updates on copies first to keep records unchanged on error
+	oldIDs := map[interface{}]bool{}
+	newIDs := map[interface{}]reflect.Value{}
+	for _, record := range records {
+		id, err := d.id(record)
+		if err != nil {
+			return err
+		}
+		oldIDs[id] = true
+
+		record = deepCopy(record)
+		for _, values := range []map[string]interface{}{updates, tracking} {
+			for field, value := range values {
+				if err := d.set(record, field, value); err != nil {
+					return err
+				}
+			}
+		}
+		if d.versionField != "" {
+			if err := d.increment(record, d.versionField); err != nil {
+				return err
+			}
+		}
+
+		// Check primary key changes
+		if id, err = d.id(record); err != nil {
+			return err
+		}
+		if _, ok := newIDs[id]; ok {
+			return errors.Newf(errors.AlreadyExists, nil, "memory: record '%v' already exists", id)
+		}
+		newIDs[id] = record
+	}
+	for id := range newIDs {
+		if _, ok := d.records[id]; ok && !oldIDs[id] {
+			return errors.Newf(errors.AlreadyExists, nil, "memory: record '%v' already exists", id)
+		}
+	}
+
+	// Replace records
+	for id := range oldIDs {
+		delete(d.records, id)
+	}
+	for id, record := range newIDs {
+		d.records[id] = record
+	}
+
+	// Return no error
+	return nil
+}
+
+// RemoveOne is used to remove one element from the collection that match the filter
+func (d *Default) RemoveOne(ctx context.Context, filter interface{}) error {
+	d.Lock()
+	defer d.Unlock()
+
+	records, err := d.find(filter)
+	if err != nil {
+		return err
+	}
+
+	// If no records where affected return an handled error
+	if len(records) == 0 {
+		return db.ErrNoModification
+	}
+
+	// Remove first record in primary key order
+	id, err := d.id(records[0])
+	if err != nil {
+		return err
+	}
+
+	// Mark as deleted
+	if d.tracking.SoftDelete() {
+		record := deepCopy(records[0])
+		for field, value := range d.tracking.OnDelete(ctx, time.Now().UTC()) {
+			if err := d.set(record, field, value); err != nil {
+				return err
+			}
+		}
+		d.records[id] = record
+		return nil
+	}
+
+	delete(d.records, id)
+
+	// Return no error
+	return nil
+}
+
+// Search for element in collection
+func (d *Default) Search(ctx context.Context, filter interface{}, pagination *db.Pagination, sortParams *db.SortParameters, results interface{}) (int, error) {
+	d.RLock()
+	defer d.RUnlock()
+
+	// Check sort parameters
+	sorts, err := d.sorts(sortParams)
+	if err != nil {
+		return 0, err
+	}
+
+	// Check results type
+	dest := reflect.ValueOf(results)
+	if dest.Kind() != reflect.Ptr || dest.IsNil() || dest.Elem().Kind() != reflect.Slice {
+		return 0, errors.Newf(errors.InvalidArgument, nil, "memory: results must be a pointer to a slice, got '%T'", results)
+	}
+	items := dest.Elem()
+	byRef := items.Type().Elem().Kind() == reflect.Ptr
+	if elem := items.Type().Elem(); elem != d.recordType && (!byRef || elem.Elem() != d.recordType) {
+		return 0, errors.Newf(errors.InvalidArgument, nil, "memory: results must be a slice of '%s', got '%T'", d.recordType, results)
+	}
+
+	records, err := d.find(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	// Apply sort parameters
+	d.sort(records, sorts)
+
+	// Apply pagination
+	count, hasNext := len(records), false
+	switch {
+	case pagination != nil && pagination.IsKeyset():
+		// Check cursor origin
+		if err := pagination.Bind(d.table, filter, sorts); err != nil {
+			return 0, err
+		}
+		if records, err = d.after(records, sorts, pagination.After()); err != nil {
+			return 0, err
+		}
+		if hasNext = len(records) > int(pagination.PerPage); hasNext {
+			records = records[:pagination.PerPage]
+		}
+
+		// Total count is not computed for keyset pagination
+		count = len(records)
+		if count == 0 && len(pagination.After()) == 0 {
+			return 0, db.ErrNoResult
+		}
+	case count == 0:
+		return 0, db.ErrNoResult
+	case pagination != nil:
+		pagination.SetTotal(uint(count))
+		records = records[minInt(int(pagination.Offset()), len(records)):]
+		records = records[:minInt(int(pagination.PerPage), len(records))]
+	default:
+	}
+
+	// Copy records to results
+	res := reflect.MakeSlice(items.Type(), 0, len(records))
+	for _, record := range records {
+		item := deepCopy(record)
+		if byRef {
+			item = item.Addr()
+		}
+		res = reflect.Append(res, item)
+	}
+	items.Set(res)
+
+	// Update cursor
+	if pagination != nil && pagination.IsKeyset() {
+		if len(records) == 0 {
+			return count, pagination.SetNext(nil, false)
+		}
+
+		last := records[len(records)-1]
+		values := make([]interface{}, len(sorts))
+		for i, f := range sorts {
+			values[i] = d.field(last, f.Field)
+		}
+		return count, pagination.SetNext(values, hasNext)
+	}
+
+	// Return no error
+	return count, nil
+}
+
+// -----------------------------------------------------------------------------
+
+// find returns the records matching the given filter in primary key order,
+// records marked as deleted are excluded.
+func (d *Default) find(filter interface{}) ([]reflect.Value, error) {
+	match, err := d.where(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []reflect.Value{}
+	for _, record := range d.records {
+		if !d.deleted(record) && match(record) {
+			res = append(res, record)
+		}
+	}
+	d.sort(res, nil)
+
+	return res, nil
+}
+
+// after returns the sorted records located strictly after the given sort key
+// values.
+func (d *Default) after(records []reflect.Value, sorts db.SortParameters, after []interface{}) ([]reflect.Value, error) {
+	if len(after) == 0 {
+		return records, nil
+	}
+
+	c, err := db.KeysetCriterion(sorts, after)
+	if err != nil {
+		return nil, err
+	}
+	match, err := d.matchCriterion(c)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, record := range records {
+		if match(record) {
+			return records[i:], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// sort orders records using the given sort parameters, records are ordered
+// by primary key by default.
+func (d *Default) sort(records []reflect.Value, sorts db.SortParameters) {
+	sorts = sorts.TieBreak(d.primaryKey)
+
+	sort.SliceStable(records, func(i, j int) bool {
+		for _, f := range sorts {
+			res, _ := compare(d.field(records[i], f.Field), d.field(records[j], f.Field))
+			if res == 0 {
+				continue
+			}
+			if f.Direction == db.Descending {
+				return res > 0
+			}
+			return res < 0
+		}
+		return false
+	})
+}
+
+// sorts checks the given sort parameters against sortable fields, and adds
+// the primary key as tie-breaker.
+func (d *Default) sorts(params *db.SortParameters) (db.SortParameters, error) {
+	if params == nil {
+		params = &db.SortParameters{}
+	}
+
+	// Check sortable fields
+	if err := params.Validate(func(field string) bool {
+		return field == d.primaryKey || d.hasField(field) && (len(d.sortableFields) == 0 || d.sortableFields[field])
+	}); err != nil {
+		return nil, err
+	}
+
+	return params.TieBreak(d.primaryKey), nil
+}
+
+// where converts the given filter to a record matcher, backend-neutral
+// criteria, predicates and field equality maps are supported.
+func (d *Default) where(filter interface{}) (matcher, error) {
+	switch f := filter.(type) {
+	case nil:
+		return func(reflect.Value) bool { return true }, nil
+	case *db.Criterion:
+		if err := f.Validate(func(field string) bool {
+			return len(d.filterableFields) == 0 || d.filterableFields[field]
+		}); err != nil {
+			return nil, err
+		}
+		return d.matchCriterion(f)
+	case Predicate:
+		return func(record reflect.Value) bool { return f(deepCopy(record).Interface()) }, nil
+	case func(interface{}) bool:
+		return func(record reflect.Value) bool { return f(deepCopy(record).Interface()) }, nil
+	case map[string]interface{}:
+		conds := make([]*db.Criterion, 0, len(f))
+		for field, value := range f {
+			conds = append(conds, db.Eq(field, value))
+		}
+		if len(conds) == 0 {
+			return d.where(nil)
+		}
+		return d.matchCriterion(db.And(conds...))
+	default:
+	}
+
+	return nil, errors.Newf(errors.InvalidArgument, nil, "memory: unsupported filter type '%T'", filter)
+}
+
+// field returns the value of the given record field, nil if missing.
+func (d *Default) field(record reflect.Value, name string) interface{} {
+	fi, ok := d.mapper.TypeMap(record.Type()).Names[name]
+	if !ok {
+		return nil
+	}
+	return reflectx.FieldByIndexesReadOnly(record, fi.Index).Interface()
+}
+
+// hasField returns true if the given field is part of the record type, all
+// fields are accepted before the first record creation.
+func (d *Default) hasField(name string) bool {
+	if d.recordType == nil {
+		return true
+	}
+	_, ok := d.mapper.TypeMap(d.recordType).Names[name]
+	return ok
+}
+
+// deleted returns true if the given record is marked as deleted.
+func (d *Default) deleted(record reflect.Value) bool {
+	if !d.tracking.SoftDelete() {
+		return false
+	}
+
+	value := d.field(record, d.tracking.DeletedAt)
+	if valuer, ok := value.(driver.Valuer); ok && !isNull(value) {
+		value, _ = valuer.Value()
+	}
+
+	return !isNull(value)
+}
+
+// set assigns a copy of the given value to the field of an addressable record,
+// numbers are converted to the field type, pointers are allocated and
+// sql.Scanner fields scan the value.
+func (d *Default) set(record reflect.Value, name string, value interface{}) error {
+	fi, ok := d.mapper.TypeMap(record.Type()).Names[name]
+	if !ok {
+		return errors.Newf(errors.InvalidArgument, nil, "memory: unknown field '%s'", name)
+	}
+	field := reflectx.FieldByIndexes(record, fi.Index)
+
+	v := reflect.ValueOf(value)
+	switch {
+	case value == nil:
+		field.Set(reflect.Zero(field.Type()))
+	case v.Type().AssignableTo(field.Type()):
+		field.Set(deepCopy(v))
+	case field.Kind() == reflect.Ptr && v.Type().AssignableTo(field.Type().Elem()):
+		ptr := reflect.New(field.Type().Elem())
+		ptr.Elem().Set(deepCopy(v))
+		field.Set(ptr)
+	case isNumber(v) && isNumber(field):
+		field.Set(v.Convert(field.Type()))
+	case field.Addr().Type().Implements(scannerType):
+		if err := field.Addr().Interface().(sql.Scanner).Scan(value); err != nil {
+			return errors.Newf(errors.InvalidArgument, err, "memory: unable to assign '%T' to field '%s'", value, name)
+		}
+	default:
+		return errors.Newf(errors.InvalidArgument, nil, "memory: unable to assign '%T' to field '%s'", value, name)
+	}
+
+	return nil
+}
+
+// increment adds one to the integer field of an addressable record.
+func (d *Default) increment(record reflect.Value, name string) error {
+	fi, ok := d.mapper.TypeMap(record.Type()).Names[name]
+	if !ok {
+		return errors.Newf(errors.InvalidArgument, nil, "memory: unknown field '%s'", name)
+	}
+	field := reflectx.FieldByIndexes(record, fi.Index)
+
+	switch {
+	case isInt(field):
+		field.SetInt(field.Int() + 1)
+	case isUint(field):
+		field.SetUint(field.Uint() + 1)
+	default:
+		return errors.Newf(errors.InvalidArgument, nil, "memory: version field '%s' must be an integer", name)
+	}
+
+	return nil
+}
+
+// id returns the primary key value of the given record.
+func (d *Default) id(record reflect.Value) (interface{}, error) {
+	fi, ok := d.mapper.TypeMap(record.Type()).Names[d.primaryKey]
+	if !ok {
+		return nil, errors.Newf(errors.InvalidArgument, nil, "memory: record has no primary key field '%s'", d.primaryKey)
+	}
+	if !fi.Field.Type.Comparable() {
+		return nil, errors.Newf(errors.InvalidArgument, nil, "memory: primary key field '%s' must be comparable", d.primaryKey)
+	}
+
+	return reflectx.FieldByIndexesReadOnly(record, fi.Index).Interface(), nil
+}
+
+// -----------------------------------------------------------------------------
+
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// deepCopy returns an addressable copy of the given value, pointers, slices,
+// maps and exported struct fields are copied recursively. Values must not
+// contain reference cycles.
+func deepCopy(value reflect.Value) reflect.Value {
+	res := reflect.New(value.Type()).Elem()
+
+	switch value.Kind() {
+	case reflect.Ptr:
+		if !value.IsNil() {
+			res.Set(deepCopy(value.Elem()).Addr())
+		}
+	case reflect.Interface:
+		if !value.IsNil() {
+			res.Set(deepCopy(value.Elem()))
+		}
+	case reflect.Slice:
+		if !value.IsNil() {
+			res.Set(reflect.MakeSlice(value.Type(), value.Len(), value.Len()))
+			for i := 0; i < value.Len(); i++ {
+				res.Index(i).Set(deepCopy(value.Index(i)))
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			res.Index(i).Set(deepCopy(value.Index(i)))
+		}
+	case reflect.Map:
+		if !value.IsNil() {
+			res.Set(reflect.MakeMapWithSize(value.Type(), value.Len()))
+			iter := value.MapRange()
+			for iter.Next() {
+				res.SetMapIndex(deepCopy(iter.Key()), deepCopy(iter.Value()))
+			}
+		}
+	case reflect.Struct:
+		// Unexported fields are shallow copied
+		res.Set(value)
+		for i := 0; i < value.NumField(); i++ {
+			if field := res.Field(i); field.CanSet() {
+				field.Set(deepCopy(value.Field(i)))
+			}
+		}
+	default:
+		res.Set(value)
+	}
+
+	return res
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
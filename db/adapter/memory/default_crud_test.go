@@ -0,0 +1,340 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/xerrors"
+
+	"go.zenithar.org/pkg/db"
+	"go.zenithar.org/pkg/errors"
+	"go.zenithar.org/pkg/types"
+)
+
+type user struct {
+	ID   string `db:"id"`
+	Name string `db:"name"`
+	Age  int    `db:"age"`
+}
+
+func setup(t *testing.T, opts ...Option) *Default {
+	underTest := NewCRUDTable("test", "users", opts...)
+	for i, name := range []string{"alice", "bob", "carol", "dave", "eve"} {
+		if err := underTest.Create(context.Background(), &user{ID: fmt.Sprintf("%d", i+1), Name: name, Age: 20 + i}); err != nil {
+			t.Fatalf("error must not be raised, got %v", err)
+		}
+	}
+	return underTest
+}
+
+func TestDefault_CRUD(t *testing.T) {
+	ctx := context.Background()
+	underTest := setup(t)
+
+	// Duplicate primary key
+	err := underTest.Create(ctx, user{ID: "1", Name: "mallory"})
+	var e *errors.Error
+	if !xerrors.As(err, &e) || e.Code != errors.AlreadyExists {
+		t.Fatalf("expected already exists error, got %v", err)
+	}
+
+	// Fetch one
+	var got user
+	if err := underTest.WhereAndFetchOne(ctx, db.Eq("name", "bob"), &got); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if diff := cmp.Diff(user{ID: "2", Name: "bob", Age: 21}, got); diff != "" {
+		t.Errorf("%s", diff)
+	}
+	if err := underTest.WhereAndFetchOne(ctx, db.Eq("name", "mallory"), &got); !xerrors.Is(err, db.ErrNoResult) {
+		t.Fatalf("expected no result error, got %v", err)
+	}
+
+	// Returned records are copies
+	got.Name = "robert"
+	if count, _ := underTest.WhereCount(ctx, db.Eq("name", "robert")); count != 0 {
+		t.Fatalf("stored record must not be modified")
+	}
+
+	// Update
+	if err := underTest.Update(ctx, map[string]interface{}{"age": int64(42)}, map[string]interface{}{"id": "2"}); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	count, err := underTest.WhereCount(ctx, Predicate(func(record interface{}) bool {
+		return record.(user).Age == 42
+	}))
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if count != 1 {
+		t.Errorf("got %d, wanted 1", count)
+	}
+	if err := underTest.Update(ctx, map[string]interface{}{"age": "old"}, db.Eq("id", "2")); err == nil {
+		t.Fatalf("expected error mst be raised")
+	}
+	if err := underTest.Update(ctx, map[string]interface{}{"id": "3"}, db.Eq("id", "2")); err == nil {
+		t.Fatalf("expected error mst be raised")
+	}
+	if err := underTest.Update(ctx, map[string]interface{}{"age": 42}, db.Eq("id", "42")); !xerrors.Is(err, db.ErrNoModification) {
+		t.Fatalf("expected no modification error, got %v", err)
+	}
+
+	// Remove
+	if err := underTest.RemoveOne(ctx, db.Eq("id", "2")); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if err := underTest.RemoveOne(ctx, db.Eq("id", "2")); !xerrors.Is(err, db.ErrNoModification) {
+		t.Fatalf("expected no modification error, got %v", err)
+	}
+
+	// Unsupported filter
+	if _, err := underTest.WhereCount(ctx, 42); err == nil {
+		t.Fatalf("expected error mst be raised")
+	}
+}
+
+type document struct {
+	ID        string            `db:"id"`
+	Title     string            `db:"title"`
+	Version   int               `db:"version"`
+	Tags      []string          `db:"tags"`
+	Meta      map[string]string `db:"meta"`
+	CreatedAt *time.Time        `db:"created_at"`
+	UpdatedAt *time.Time        `db:"updated_at"`
+	DeletedAt *time.Time        `db:"deleted_at"`
+	CreatedBy sql.NullString    `db:"created_by"`
+	UpdatedBy sql.NullString    `db:"updated_by"`
+}
+
+func TestDefault_Tracking(t *testing.T) {
+	ctx := types.WithActor(context.Background(), "alice")
+
+	underTest := NewCRUDTable("test", "documents",
+		WithVersionField("version"),
+		WithTimestamps("created_at", "updated_at"),
+		WithSoftDelete("deleted_at"),
+		WithAudit("created_by", "updated_by"),
+	)
+
+	// Create
+	doc := &document{ID: "1", Title: "draft", Version: 1, Tags: []string{"a"}, Meta: map[string]string{"k": "v"}}
+	if err := underTest.Create(ctx, doc); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	var got document
+	if err := underTest.WhereAndFetchOne(ctx, db.Eq("id", "1"), &got); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if got.CreatedAt == nil || got.UpdatedAt == nil || got.DeletedAt != nil || got.CreatedBy.String != "alice" {
+		t.Fatalf("tracking fields must be set on creation, got %+v", got)
+	}
+
+	// Stored records are deep copies
+	doc.Tags[0], doc.Meta["k"] = "created", "created"
+	got.Tags[0], got.Meta["k"] = "fetched", "fetched"
+	if err := underTest.WhereAndFetchOne(ctx, db.Eq("id", "1"), &got); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if got.Tags[0] != "a" || got.Meta["k"] != "v" {
+		t.Fatalf("stored record must not be modified, got %+v", got)
+	}
+
+	// Update with expected version
+	if err := underTest.Update(types.WithActor(ctx, "bob"), map[string]interface{}{"title": "final", "version": 1}, db.Eq("id", "1")); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if err := underTest.WhereAndFetchOne(ctx, db.Eq("id", "1"), &got); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if got.Title != "final" || got.Version != 2 || got.UpdatedBy.String != "bob" || got.CreatedBy.String != "alice" {
+		t.Fatalf("update must increment version and track actor, got %+v", got)
+	}
+
+	// Stale version
+	err := underTest.Update(ctx, map[string]interface{}{"title": "stale", "version": 1}, db.Eq("id", "1"))
+	var e *errors.Error
+	if !xerrors.As(err, &e) || e.Code != errors.Aborted || !xerrors.Is(err, db.ErrVersionConflict) {
+		t.Fatalf("expected version conflict error, got %v", err)
+	}
+	if err := underTest.Update(ctx, map[string]interface{}{"title": "stale", "version": 2}, db.Eq("id", "42")); !xerrors.Is(err, db.ErrNoModification) {
+		t.Fatalf("expected no modification error, got %v", err)
+	}
+
+	// Missing version
+	if err := underTest.Update(ctx, map[string]interface{}{"title": "stale"}, db.Eq("id", "1")); err == nil {
+		t.Fatalf("expected error mst be raised")
+	}
+
+	// Soft delete
+	if err := underTest.RemoveOne(ctx, db.Eq("id", "1")); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if err := underTest.WhereAndFetchOne(ctx, db.Eq("id", "1"), &got); !xerrors.Is(err, db.ErrNoResult) {
+		t.Fatalf("expected no result error, got %v", err)
+	}
+	if count, _ := underTest.WhereCount(ctx, nil); count != 0 {
+		t.Fatalf("deleted records must be excluded, got %d", count)
+	}
+	if err := underTest.RemoveOne(ctx, db.Eq("id", "1")); !xerrors.Is(err, db.ErrNoModification) {
+		t.Fatalf("expected no modification error, got %v", err)
+	}
+	if err := underTest.Create(ctx, &document{ID: "1"}); err == nil {
+		t.Fatalf("expected error mst be raised")
+	}
+}
+
+func TestDefault_Where(t *testing.T) {
+	testCases := []struct {
+		name    string
+		opts    []Option
+		filter  interface{}
+		want    []string
+		wantErr bool
+	}{
+		{name: "no filter", want: []string{"1", "2", "3", "4", "5"}},
+		{name: "in", filter: db.In("name", "bob", "eve"), want: []string{"2", "5"}},
+		{name: "like", filter: db.Like("name", "%a%"), want: []string{"1", "3", "4"}},
+		{name: "between", filter: db.Between("age", 21, 23), want: []string{"2", "3", "4"}},
+		{name: "or", filter: db.Or(db.Lt("age", 21), db.Eq("name", "eve")), want: []string{"1", "5"}},
+		{name: "not", filter: db.Not(db.Neq("name", "carol")), want: []string{"3"}},
+		{name: "missing field", filter: db.IsNull("email"), want: []string{"1", "2", "3", "4", "5"}},
+		{name: "predicate", filter: func(record interface{}) bool { return record.(user).Age%2 == 0 }, want: []string{"1", "3", "5"}},
+		{name: "map", filter: map[string]interface{}{"name": "dave", "age": 23}, want: []string{"4"}},
+		{name: "not filterable", opts: []Option{WithFilterableFields("id")}, filter: db.Eq("name", "bob"), wantErr: true},
+		{name: "invalid criteria", filter: db.And(), wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			underTest := setup(t, tt.opts...)
+
+			var results []*user
+			_, err := underTest.Search(context.Background(), tt.filter, nil, nil, &results)
+			if tt.wantErr && err == nil {
+				t.Fatalf("expected error mst be raised")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("error must not be raised, got %v", err)
+			}
+
+			var got []string
+			for _, u := range results {
+				got = append(got, u.ID)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("%s", diff)
+			}
+		})
+	}
+}
+
+func TestDefault_Search(t *testing.T) {
+	ctx := context.Background()
+	underTest := setup(t, WithSortableFields("name", "age"))
+
+	// Offset pagination
+	var results []user
+	pagination := db.NewPaginator(2, 2)
+	count, err := underTest.Search(ctx, db.Gt("age", 20), pagination, db.SortConverter([]string{"-age"}), &results)
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if count != 4 || pagination.Total() != 4 {
+		t.Errorf("got %d, wanted 4", count)
+	}
+	if diff := cmp.Diff([]user{{ID: "3", Name: "carol", Age: 22}, {ID: "2", Name: "bob", Age: 21}}, results); diff != "" {
+		t.Errorf("%s", diff)
+	}
+
+	// Keyset pagination
+	codec := db.NewCursorCodec([]byte("secret"))
+	var names []string
+	cursor := ""
+	for {
+		pagination, err := db.NewCursorPaginator(codec, cursor, 2)
+		if err != nil {
+			t.Fatalf("error must not be raised, got %v", err)
+		}
+
+		var page []user
+		if _, err := underTest.Search(ctx, nil, pagination, db.SortConverter([]string{"-name"}), &page); err != nil {
+			t.Fatalf("error must not be raised, got %v", err)
+		}
+		for _, u := range page {
+			names = append(names, u.Name)
+		}
+
+		if cursor = pagination.NextCursor(); cursor == "" {
+			break
+		}
+	}
+	if diff := cmp.Diff([]string{"eve", "dave", "carol", "bob", "alice"}, names); diff != "" {
+		t.Errorf("%s", diff)
+	}
+
+	// Cursor reused with another filter
+	first, _ := db.NewCursorPaginator(codec, "", 2)
+	if _, err := underTest.Search(ctx, nil, first, db.SortConverter([]string{"-name"}), &results); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	next, err := db.NewCursorPaginator(codec, first.NextCursor(), 2)
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if _, err := underTest.Search(ctx, db.Gt("age", 20), next, db.SortConverter([]string{"-name"}), &results); err == nil {
+		t.Fatalf("expected error mst be raised")
+	}
+
+	// Not sortable field
+	if _, err := underTest.Search(ctx, nil, nil, db.SortConverter([]string{"unknown"}), &results); err == nil {
+		t.Fatalf("expected error mst be raised")
+	}
+
+	// Invalid results
+	var invalid []string
+	if _, err := underTest.Search(ctx, nil, nil, nil, &invalid); err == nil {
+		t.Fatalf("expected error mst be raised")
+	}
+
+	// No result
+	if _, err := underTest.Search(ctx, db.Eq("name", "mallory"), nil, nil, &results); !xerrors.Is(err, db.ErrNoResult) {
+		t.Fatalf("expected no result error, got %v", err)
+	}
+}
+
+func TestDefault_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	underTest := NewCRUDTable("test", "users")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			id := fmt.Sprintf("%02d", i)
+			if err := underTest.Create(ctx, &user{ID: id, Age: i}); err != nil {
+				t.Errorf("error must not be raised, got %v", err)
+			}
+			if err := underTest.Update(ctx, map[string]interface{}{"name": id}, db.Eq("id", id)); err != nil {
+				t.Errorf("error must not be raised, got %v", err)
+			}
+			if _, err := underTest.WhereCount(ctx, nil); err != nil {
+				t.Errorf("error must not be raised, got %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if count, _ := underTest.WhereCount(ctx, db.Like("name", "%")); count != 50 {
+		t.Fatalf("got %d, wanted 50", count)
+	}
+}
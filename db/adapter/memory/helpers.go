@@ -0,0 +1,133 @@
+package memory
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// compare returns the order of the given values, numbers of different types
+// are compared by value. False is returned for incomparable values.
+func compare(a, b interface{}) (int, bool) {
+	a, b = indirect(a), indirect(b)
+	if a == nil || b == nil {
+		return 0, false
+	}
+
+	// Timestamps
+	if ta, ok := a.(time.Time); ok {
+		tb, ok := b.(time.Time)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case ta.Before(tb):
+			return -1, true
+		case ta.After(tb):
+			return 1, true
+		default:
+			return 0, true
+		}
+	}
+
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch {
+	case isInt(va) && isInt(vb):
+		return order(va.Int() < vb.Int(), va.Int() > vb.Int()), true
+	case isUint(va) && isUint(vb):
+		return order(va.Uint() < vb.Uint(), va.Uint() > vb.Uint()), true
+	case isNumber(va) && isNumber(vb):
+		fa, fb := toFloat(va), toFloat(vb)
+		return order(fa < fb, fa > fb), true
+	case va.Kind() == reflect.String && vb.Kind() == reflect.String:
+		return strings.Compare(va.String(), vb.String()), true
+	case va.Kind() == reflect.Bool && vb.Kind() == reflect.Bool:
+		return order(!va.Bool() && vb.Bool(), va.Bool() && !vb.Bool()), true
+	default:
+	}
+
+	// Other types only support equality
+	if reflect.DeepEqual(a, b) {
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// isNull returns true for nil values and nil pointers, maps and slices.
+func isNull(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return v.IsNil()
+	default:
+	}
+
+	return false
+}
+
+// -----------------------------------------------------------------------------
+
+func indirect(value interface{}) interface{} {
+	if isNull(value) {
+		return nil
+	}
+
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	return v.Interface()
+}
+
+func order(lower, greater bool) int {
+	switch {
+	case lower:
+		return -1
+	case greater:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func isInt(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	default:
+	}
+	return false
+}
+
+func isUint(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	default:
+	}
+	return false
+}
+
+func isNumber(v reflect.Value) bool {
+	return isInt(v) || isUint(v) || v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64
+}
+
+func toFloat(v reflect.Value) float64 {
+	switch {
+	case isInt(v):
+		return float64(v.Int())
+	case isUint(v):
+		return float64(v.Uint())
+	default:
+	}
+	return v.Float()
+}
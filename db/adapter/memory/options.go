@@ -0,0 +1,80 @@
+package memory
+
+// Option defines table option builder.
+type Option func(*Default)
+
+// WithTagName sets the struct tag used to name record fields, 'db' by default.
+func WithTagName(tag string) Option {
+	return func(d *Default) {
+		d.tagName = tag
+	}
+}
+
+// WithPrimaryKey sets the primary key field used to identify records and as
+// sort tie-breaker, 'id' by default.
+func WithPrimaryKey(field string) Option {
+	return func(d *Default) {
+		d.primaryKey = field
+	}
+}
+
+// WithFilterableFields sets the fields allowed in backend-neutral criteria,
+// all fields are allowed by default.
+func WithFilterableFields(fields ...string) Option {
+	return func(d *Default) {
+		d.filterableFields = toSet(fields)
+	}
+}
+
+// WithSortableFields sets the fields allowed in sort parameters, all fields are
+// allowed by default.
+func WithSortableFields(fields ...string) Option {
+	return func(d *Default) {
+		d.sortableFields = toSet(fields)
+	}
+}
+
+// WithVersionField enables optimistic concurrency control using the given
+// integer field, updates must contain the expected version which is
+// incremented.
+func WithVersionField(field string) Option {
+	return func(d *Default) {
+		d.versionField = field
+	}
+}
+
+// WithTimestamps sets the fields automatically set to the current time on
+// creation and update.
+func WithTimestamps(createdAt, updatedAt string) Option {
+	return func(d *Default) {
+		d.tracking.CreatedAt = createdAt
+		d.tracking.UpdatedAt = updatedAt
+	}
+}
+
+// WithSoftDelete marks removed records by setting the given timestamp field
+// instead of deleting them, marked records are excluded from all queries.
+func WithSoftDelete(deletedAt string) Option {
+	return func(d *Default) {
+		d.tracking.DeletedAt = deletedAt
+	}
+}
+
+// WithAudit sets the fields recording the acting principal from context on
+// creation and update.
+func WithAudit(createdBy, updatedBy string) Option {
+	return func(d *Default) {
+		d.tracking.CreatedBy = createdBy
+		d.tracking.UpdatedBy = updatedBy
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func toSet(values []string) map[string]bool {
+	res := map[string]bool{}
+	for _, v := range values {
+		res[v] = true
+	}
+	return res
+}
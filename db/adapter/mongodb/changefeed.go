@@ -0,0 +1,85 @@
+package mongodb
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"go.zenithar.org/pkg/db"
+	"go.zenithar.org/pkg/errors"
+	"go.zenithar.org/pkg/log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+var _ db.ChangeFeed = (*Default)(nil)
+
+type changeEvent struct {
+	OperationType string `bson:"operationType"`
+	FullDocument  bson.M `bson:"fullDocument"`
+	DocumentKey   bson.M `bson:"documentKey"`
+}
+
+// Watch emits collection changes using change streams, the previous values
+// are not available and only the document key is provided on deletion.
+func (d *Default) Watch(ctx context.Context, resumeToken string, fn db.ChangeHandler) error {
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+
+	// Resume after the given token
+	if resumeToken != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(resumeToken)
+		if err != nil {
+			return errors.Newf(errors.InvalidArgument, err, "mongodb: invalid resume token")
+		}
+		opts.SetResumeAfter(bson.Raw(raw))
+	}
+
+	stream, err := d.session.Database(d.db).Collection(d.table).Watch(ctx, mongo.Pipeline{}, opts)
+	if err != nil {
+		return wrapError(err, "mongodb: unable to open change stream")
+	}
+	defer func() {
+		if err := stream.Close(context.Background()); err != nil {
+			log.For(ctx).Error("Unable to close change stream", zap.Error(err))
+		}
+	}()
+
+	for stream.Next(ctx) {
+		var evt changeEvent
+		if err := stream.Decode(&evt); err != nil {
+			return fmt.Errorf("mongodb: unable to decode change event: %w", err)
+		}
+
+		change := &db.Change{
+			Table:       d.table,
+			ResumeToken: base64.RawURLEncoding.EncodeToString(stream.ResumeToken()),
+		}
+		switch evt.OperationType {
+		case "insert":
+			change.Type = db.ChangeInsert
+			change.New = evt.FullDocument
+		case "update", "replace":
+			change.Type = db.ChangeUpdate
+			change.New = evt.FullDocument
+		case "delete":
+			change.Type = db.ChangeDelete
+			change.Old = evt.DocumentKey
+		default:
+			// Ignore collection level events
+			continue
+		}
+
+		if err := fn(ctx, change); err != nil {
+			return err
+		}
+	}
+
+	if err := stream.Err(); err != nil && ctx.Err() == nil {
+		return wrapError(err, "mongodb: change stream failed")
+	}
+
+	return ctx.Err()
+}
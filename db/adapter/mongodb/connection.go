@@ -5,20 +5,34 @@ import (
 	"fmt"
 	"time"
 
+	"go.zenithar.org/pkg/errors"
 	"go.zenithar.org/pkg/log"
+	"go.zenithar.org/pkg/tlsconfig"
 
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/connstring"
 	"go.uber.org/zap"
 )
 
 // Configuration repesents database connection configuration
 type Configuration struct {
-	AutoMigrate      bool
-	ConnectionString string
-	DatabaseName     string
-	Username         string
-	Password         string
+	AutoMigrate            bool              `toml:"autoMigrate" default:"false" comment:"Apply pending schema migrations on startup"`
+	ConnectionString       string            `toml:"connectionString" default:"mongodb://localhost:27017" comment:"Database connection string"`
+	DatabaseName           string            `toml:"databaseName" comment:"Database name (defaults to connection string one)"`
+	Username               string            `toml:"username" comment:"Database user (overrides connection string one)"`
+	Password               string            `toml:"password" comment:"Database password (overrides connection string one)"`
+	AuthSource             string            `toml:"authSource" comment:"Authentication database (defaults to database name)"`
+	MaxPoolSize            uint64            `toml:"maxPoolSize" default:"100" comment:"Maximum number of connections per server (0 for connection string or driver default)"`
+	MinPoolSize            uint64            `toml:"minPoolSize" default:"0" comment:"Minimum number of connections per server (0 for connection string or driver default)"`
+	MaxConnIdleTime        time.Duration     `toml:"maxConnIdleTime" default:"0s" comment:"Maximum amount of time a connection may be idle (0 for unlimited)"`
+	ConnectTimeout         time.Duration     `toml:"connectTimeout" default:"10s" comment:"Timeout of connection establishment (10s if 0)"`
+	ServerSelectionTimeout time.Duration     `toml:"serverSelectionTimeout" default:"30s" comment:"Timeout of server selection for each operation"`
+	SocketTimeout          time.Duration     `toml:"socketTimeout" default:"0s" comment:"Timeout of socket reads and writes (0 for unlimited)"`
+	DisableRetryWrites     bool              `toml:"disableRetryWrites" default:"false" comment:"Don't retry failed writes once"`
+	DisableRetryReads      bool              `toml:"disableRetryReads" default:"false" comment:"Don't retry failed reads once"`
+	UseTLS                 bool              `toml:"useTLS" default:"false" comment:"Enable TLS with host name verification"`
+	TLS                    tlsconfig.Options `toml:"TLS" comment:"TLS settings"`
 }
 
 // Connection provides Wire provider for a MongoDB database connection
@@ -26,11 +40,73 @@ func Connection(ctx context.Context, cfg *Configuration) (*mongo.Client, error)
 
 	log.For(ctx).Info("Trying to connect to MongoDB servers ...")
 
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
-	defer cancel()
-
 	// Extract database name from connection string
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.ConnectionString))
+	databaseName, err := cfg.database()
+	if err != nil {
+		return nil, err
+	}
+
+	// Unset values keep connection string or driver defaults
+	connectTimeout := cfg.ConnectTimeout
+	if connectTimeout == 0 {
+		connectTimeout = 10 * time.Second
+	}
+
+	opts := options.Client().ApplyURI(cfg.ConnectionString)
+	if connectTimeout > 0 {
+		opts.SetConnectTimeout(connectTimeout)
+	}
+	if cfg.MaxPoolSize > 0 {
+		opts.SetMaxPoolSize(cfg.MaxPoolSize)
+	}
+	if cfg.MinPoolSize > 0 {
+		opts.SetMinPoolSize(cfg.MinPoolSize)
+	}
+	if cfg.DisableRetryWrites {
+		opts.SetRetryWrites(false)
+	}
+	if cfg.DisableRetryReads {
+		opts.SetRetryReads(false)
+	}
+	if cfg.MaxConnIdleTime > 0 {
+		opts.SetMaxConnIdleTime(cfg.MaxConnIdleTime)
+	}
+	if cfg.ServerSelectionTimeout > 0 {
+		opts.SetServerSelectionTimeout(cfg.ServerSelectionTimeout)
+	}
+	if cfg.SocketTimeout > 0 {
+		opts.SetSocketTimeout(cfg.SocketTimeout)
+	}
+
+	// Host names are verified by the driver
+	if cfg.UseTLS {
+		tlsConfig, err := tlsconfig.Client(cfg.TLS)
+		if err != nil {
+			return nil, errors.Newf(errors.InvalidArgument, err, "mongodb: invalid TLS settings")
+		}
+		opts.SetTLSConfig(tlsConfig)
+	}
+
+	// Overrides credentials
+	if cfg.Username != "" {
+		authSource := cfg.AuthSource
+		if authSource == "" {
+			authSource = databaseName
+		}
+		opts.SetAuth(options.Credential{
+			AuthSource: authSource,
+			Username:   cfg.Username,
+			Password:   cfg.Password,
+		})
+	}
+
+	if connectTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, connectTimeout)
+		defer cancel()
+	}
+
+	client, err := mongo.Connect(ctx, opts)
 	if err != nil {
 		log.For(ctx).Error("Unable to connect to MongoDB", zap.Error(err))
 		return nil, fmt.Errorf("mongodb: %w", err)
@@ -40,11 +116,29 @@ func Connection(ctx context.Context, cfg *Configuration) (*mongo.Client, error)
 	err = client.Ping(ctx, nil)
 	if err != nil {
 		log.For(ctx).Error("Unable to ping MongoDB database", zap.Error(err))
+		log.CheckErrCtx(ctx, "Unable to disconnect from MongoDB", client.Disconnect(context.Background()))
 		return nil, fmt.Errorf("mongodb: %w", err)
 	}
 
-	log.For(ctx).Info("Connected to MongoDB.")
+	log.For(ctx).Info("Connected to MongoDB.", zap.String("database", databaseName))
 
 	// Return session
 	return client, nil
 }
+
+// -----------------------------------------------------------------------------
+
+// database returns the configured database name, or the connection string one
+// if blank.
+func (cfg *Configuration) database() (string, error) {
+	if cfg.DatabaseName != "" {
+		return cfg.DatabaseName, nil
+	}
+
+	cs, err := connstring.Parse(cfg.ConnectionString)
+	if err != nil {
+		return "", fmt.Errorf("mongodb: invalid connection string: %w", err)
+	}
+
+	return cs.Database, nil
+}
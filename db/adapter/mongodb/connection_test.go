@@ -0,0 +1,38 @@
+package mongodb
+
+import "testing"
+
+func TestConfiguration_Database(t *testing.T) {
+	testCases := []struct {
+		name     string
+		cfg      Configuration
+		expected string
+	}{
+		{"explicit", Configuration{ConnectionString: "mongodb://localhost:27017/foo", DatabaseName: "bar"}, "bar"},
+		{"connection string", Configuration{ConnectionString: "mongodb://localhost:27017/foo"}, "foo"},
+		{"blank", Configuration{ConnectionString: "mongodb://localhost:27017"}, ""},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := tc.cfg
+			got, err := cfg.database()
+			if err != nil {
+				t.Fatalf("error must not be raised, got %v", err)
+			}
+			if got != tc.expected {
+				t.Errorf("got %q, wanted %q", got, tc.expected)
+			}
+			if cfg != tc.cfg {
+				t.Errorf("configuration must not be modified")
+			}
+		})
+	}
+
+	// Invalid connection string
+	if _, err := (&Configuration{ConnectionString: "foo://"}).database(); err == nil {
+		t.Fatalf("expected error mst be raised")
+	}
+}
@@ -0,0 +1,72 @@
+package mongodb
+
+import (
+	"fmt"
+	"strings"
+
+	"go.zenithar.org/pkg/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ConvertCriteria converts the given criterion to a mongodb filter, fields must
+// be part of filterable fields, all fields except operators are allowed if
+// empty.
+func ConvertCriteria(c *db.Criterion, filterableFields map[string]bool) (bson.M, error) {
+	// Check criteria first
+	if err := c.Validate(func(field string) bool {
+		if len(filterableFields) == 0 {
+			return !strings.HasPrefix(field, "$")
+		}
+		return filterableFields[field]
+	}); err != nil {
+		return nil, err
+	}
+
+	return convertCriterion(c)
+}
+
+// -----------------------------------------------------------------------------
+
+func convertCriterion(c *db.Criterion) (bson.M, error) {
+	switch c.Operator {
+	case db.OpEq:
+		return bson.M{c.Field: bson.M{"$eq": c.Values[0]}}, nil
+	case db.OpNeq:
+		return bson.M{c.Field: bson.M{"$ne": c.Values[0]}}, nil
+	case db.OpIn:
+		return bson.M{c.Field: bson.M{"$in": c.Values}}, nil
+	case db.OpGt:
+		return bson.M{c.Field: bson.M{"$gt": c.Values[0]}}, nil
+	case db.OpGte:
+		return bson.M{c.Field: bson.M{"$gte": c.Values[0]}}, nil
+	case db.OpLt:
+		return bson.M{c.Field: bson.M{"$lt": c.Values[0]}}, nil
+	case db.OpLte:
+		return bson.M{c.Field: bson.M{"$lte": c.Values[0]}}, nil
+	case db.OpLike:
+		return bson.M{c.Field: bson.M{"$regex": db.LikeToRegexp(c.Values[0].(string))}}, nil
+	case db.OpIsNull:
+		return bson.M{c.Field: nil}, nil
+	case db.OpAnd, db.OpOr, db.OpNot:
+		children := make(bson.A, 0, len(c.Children))
+		for _, child := range c.Children {
+			sub, err := convertCriterion(child)
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, sub)
+		}
+		switch c.Operator {
+		case db.OpAnd:
+			return bson.M{"$and": children}, nil
+		case db.OpOr:
+			return bson.M{"$or": children}, nil
+		default:
+			return bson.M{"$nor": children}, nil
+		}
+	default:
+	}
+
+	return nil, fmt.Errorf("mongodb: unsupported criteria operator '%d'", c.Operator)
+}
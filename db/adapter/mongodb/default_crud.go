@@ -3,29 +3,57 @@ package mongodb
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"strings"
+	"time"
 
 	"go.zenithar.org/pkg/db"
 	"go.zenithar.org/pkg/log"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+func init() {
+	// Register sort key types used in keyset pagination cursors
+	db.RegisterCursorType(primitive.ObjectID{})
+	db.RegisterCursorType(primitive.DateTime(0))
+	db.RegisterCursorType(primitive.Timestamp{})
+	db.RegisterCursorType(primitive.D{})
+}
+
 // Default contains the basic implementation of the MongoCRUD interface
 type Default struct {
 	table   string
 	db      string
 	session *mongo.Client
+
+	filterableFields map[string]bool
+	sortableFields   map[string]bool
+	primaryKey       string
+	versionField     string
+	tracking         db.Tracking
 }
 
 // NewCRUDTable sets up a new Default struct
-func NewCRUDTable(session *mongo.Client, db, table string) *Default {
-	return &Default{
-		db:      db,
-		table:   table,
-		session: session,
+func NewCRUDTable(session *mongo.Client, db, table string, opts ...Option) *Default {
+	d := &Default{
+		db:               db,
+		table:            table,
+		session:          session,
+		filterableFields: map[string]bool{},
+		sortableFields:   map[string]bool{},
+		primaryKey:       "_id",
+	}
+
+	// Apply options
+	for _, o := range opts {
+		o(d)
 	}
+
+	return d
 }
 
 // -----------------------------------------------------------------------------
@@ -52,11 +80,22 @@ func (d *Default) GetSession() interface{} {
 
 // -----------------------------------------------------------------------------
 
+// Create inserts a document into the database
+func (d *Default) Create(ctx context.Context, data interface{}) error {
+	return d.Insert(ctx, data)
+}
+
 // Insert inserts a document into the database
 func (d *Default) Insert(ctx context.Context, data interface{}) error {
+	// Apply tracking fields
+	doc, err := d.track(data, d.tracking.OnCreate(ctx, time.Now().UTC()))
+	if err != nil {
+		return err
+	}
+
 	// Run in transaction
 	return Transaction(ctx, d.session, func() error {
-		_, err := d.session.Database(d.db).Collection(d.table).InsertOne(ctx, data)
+		_, err := d.session.Database(d.db).Collection(d.table).InsertOne(ctx, doc)
 		return err
 	})
 }
@@ -70,7 +109,7 @@ func (d *Default) InsertOrUpdate(ctx context.Context, filter interface{}, data i
 	})
 }
 
-// Update performs an update on an existing resource according to passed data
+// Update performs an update on all resources matching the selector according to passed update document
 func (d *Default) Update(ctx context.Context, selector interface{}, data interface{}) error {
 	// Run in transaction
 	return Transaction(ctx, d.session, func() error {
@@ -79,6 +118,63 @@ func (d *Default) Update(ctx context.Context, selector interface{}, data interfa
 	})
 }
 
+// UpdateWhere sets the given fields of all documents matching the filter,
+// returns db.ErrNoModification if none matches.
+func (d *Default) UpdateWhere(ctx context.Context, updates map[string]interface{}, filter interface{}) error {
+	query, err := d.where(filter)
+	if err != nil {
+		return err
+	}
+
+	// Check and increment version
+	var expected interface{}
+	inc := bson.M{}
+	if d.versionField != "" {
+		expected, updates, err = db.ExtractVersion(updates, d.versionField)
+		if err != nil {
+			return err
+		}
+		query = bson.M{"$and": bson.A{query, bson.M{d.versionField: expected}}}
+		inc[d.versionField] = 1
+	}
+
+	// Apply tracking fields
+	values := bson.M{}
+	for k, v := range updates {
+		values[k] = v
+	}
+	for k, v := range d.tracking.OnUpdate(ctx, time.Now().UTC()) {
+		values[k] = v
+	}
+
+	update := bson.M{"$set": values}
+	if len(inc) > 0 {
+		update["$inc"] = inc
+	}
+
+	var matched int64
+
+	// Run in transaction
+	if err := Transaction(ctx, d.session, func() error {
+		res, err := d.session.Database(d.db).Collection(d.table).UpdateMany(ctx, query, update)
+		if err != nil {
+			return err
+		}
+		matched = res.MatchedCount
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// If no document matched return an handled error
+	if matched == 0 {
+		return d.noModification(ctx, filter, expected)
+	}
+
+	// Return no error
+	return nil
+}
+
 // UpdateID performs an update on an existing resource with ID that equals the id argument
 func (d *Default) UpdateID(ctx context.Context, id interface{}, data interface{}) error {
 	// Run in transaction
@@ -99,6 +195,48 @@ func (d *Default) DeleteAll(ctx context.Context, pred interface{}) error {
 	})
 }
 
+// RemoveOne deletes one resource that match the passed filter
+func (d *Default) RemoveOne(ctx context.Context, filter interface{}) error {
+	filter, err := d.where(filter)
+	if err != nil {
+		return err
+	}
+
+	var deleted int64
+
+	// Run in transaction
+	if err := Transaction(ctx, d.session, func() error {
+		collection := d.session.Database(d.db).Collection(d.table)
+
+		// Mark as deleted
+		if d.tracking.SoftDelete() {
+			res, err := collection.UpdateOne(ctx, filter, bson.M{"$set": d.tracking.OnDelete(ctx, time.Now().UTC())})
+			if err != nil {
+				return err
+			}
+			deleted = res.MatchedCount
+			return nil
+		}
+
+		res, err := collection.DeleteOne(ctx, filter)
+		if err != nil {
+			return err
+		}
+		deleted = res.DeletedCount
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// If no document matched return an handled error
+	if deleted == 0 {
+		return db.ErrNoModification
+	}
+
+	// Return no error
+	return nil
+}
+
 // Delete deletes a resource with specified ID
 func (d *Default) Delete(ctx context.Context, id interface{}) error {
 	// Run in transaction
@@ -164,11 +302,26 @@ func (d *Default) FindByAndFetch(ctx context.Context, key string, value interfac
 
 // WhereCount allows counting with multiple fields
 func (d *Default) WhereCount(ctx context.Context, filter interface{}) (int64, error) {
-	return d.session.Database(d.db).Collection(d.table).CountDocuments(ctx, filter)
+	filter, err := d.where(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := d.session.Database(d.db).Collection(d.table).CountDocuments(ctx, filter)
+	if err != nil {
+		return 0, wrapError(err, "mongodb: unable to count documents")
+	}
+
+	return count, nil
 }
 
 // Where allows filtering with multiple fields
 func (d *Default) Where(ctx context.Context, filter interface{}, results interface{}) error {
+	filter, err := d.where(filter)
+	if err != nil {
+		return err
+	}
+
 	res, err := d.session.Database(d.db).Collection(d.table).Find(ctx, filter)
 	if err != nil {
 		return err
@@ -178,6 +331,11 @@ func (d *Default) Where(ctx context.Context, filter interface{}, results interfa
 
 // WhereAndFetchLimit filters with multiple fields and then fills results with all found resources
 func (d *Default) WhereAndFetchLimit(ctx context.Context, filter interface{}, paginator *db.Pagination, results interface{}) error {
+	filter, err := d.where(filter)
+	if err != nil {
+		return err
+	}
+
 	limit := int64(paginator.PerPage)
 	skip := int64(paginator.Offset())
 	res, err := d.session.Database(d.db).Collection(d.table).Find(ctx, filter, &options.FindOptions{
@@ -192,27 +350,61 @@ func (d *Default) WhereAndFetchLimit(ctx context.Context, filter interface{}, pa
 
 // WhereAndFetchOne filters with multiple fields and then fills result with the first found resource
 func (d *Default) WhereAndFetchOne(ctx context.Context, filter interface{}, result interface{}) error {
-	res := d.session.Database(d.db).Collection(d.table).FindOne(ctx, filter)
-	return res.Decode(result)
+	filter, err := d.where(filter)
+	if err != nil {
+		return err
+	}
+
+	err = d.session.Database(d.db).Collection(d.table).FindOne(ctx, filter).Decode(result)
+	if err == mongo.ErrNoDocuments {
+		return db.ErrNoResult
+	} else if err != nil {
+		return wrapError(err, "mongodb: unable to retrieve document")
+	}
+
+	return nil
 }
 
 // List all entities from the database
 func (d *Default) List(ctx context.Context, sortParams *db.SortParameters, pagination *db.Pagination, results interface{}) (uint, error) {
-	// Run in transaction
 	return d.Search(ctx, bson.M{}, sortParams, pagination, results)
 }
 
 // Search all entities from the database
 func (d *Default) Search(ctx context.Context, filter interface{}, sortParams *db.SortParameters, pagination *db.Pagination, results interface{}) (uint, error) {
+	count, err := d.search(ctx, filter, pagination, sortParams, results)
+	return uint(count), err
+}
+
+// -----------------------------------------------------------------------------
+
+// search returns matching documents using offset or keyset pagination, and
+// the total count of matching documents.
+func (d *Default) search(ctx context.Context, filter interface{}, pagination *db.Pagination, sortParams *db.SortParameters, results interface{}) (int, error) {
 	// Apply Filter
-	if filter == nil {
-		filter = bson.M{}
+	query, err := d.where(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	// Check sort parameters
+	sorts, err := d.sorts(sortParams)
+	if err != nil {
+		return 0, err
+	}
+
+	// Keyset pagination, total count is not computed
+	if pagination != nil && pagination.IsKeyset() {
+		if err := pagination.Bind(d.table, filter, sorts); err != nil {
+			return 0, err
+		}
+		return d.searchAfter(ctx, query, pagination, sorts, results)
 	}
 
 	// Get total
 	count, err := d.WhereCount(ctx, filter)
 	if err != nil {
-		return 0, fmt.Errorf("mongodb: unable to count element before search: %w", err)
+		return 0, wrapError(err, "mongodb: unable to count element before search")
 	}
 	// If no result skip data request
 	if count == 0 {
@@ -227,12 +419,7 @@ func (d *Default) Search(ctx context.Context, filter interface{}, sortParams *db
 	opts := &options.FindOptions{}
 
 	// Apply sorts
-	if sortParams != nil {
-		sort := ConvertSortParameters(*sortParams)
-		if len(sort) > 0 {
-			opts.SetSort(sort)
-		}
-	}
+	opts.SetSort(ConvertSortParameters(sorts))
 
 	// Paginate
 	if pagination != nil {
@@ -241,28 +428,171 @@ func (d *Default) Search(ctx context.Context, filter interface{}, sortParams *db
 	}
 
 	// Do the query
-	cur, err := d.session.Database(d.db).Collection(d.table).Find(ctx, filter, opts)
+	cur, err := d.session.Database(d.db).Collection(d.table).Find(ctx, query, opts)
 	if err != nil {
-		return 0, fmt.Errorf("mongodb: unable to query collection: %w", err)
+		return 0, wrapError(err, "mongodb: unable to query collection")
 	}
 
 	// Extract all entities
 	if err := cur.All(ctx, results); err != nil {
-		return 0, fmt.Errorf("mongodb: unable to extract entities: %w", err)
+		return 0, wrapError(err, "mongodb: unable to extract entities")
 	}
 
 	// Check if cursor has errors
 	if err := cur.Err(); err != nil {
-		return 0, fmt.Errorf("mongodb: cursor has error: %w", err)
+		return 0, wrapError(err, "mongodb: cursor has error")
 	}
 
 	// Close the cursor
 	if err := cur.Close(ctx); err != nil {
-		return 0, fmt.Errorf("mongodb: unable to close cursor: %w", err)
+		return 0, wrapError(err, "mongodb: unable to close cursor")
 	}
 
 	// Return no error
-	return uint(count), nil
+	return int(count), nil
+}
+
+// searchAfter executes the query using keyset pagination, one more document is
+// fetched to detect the next page. It returns the page document count.
+func (d *Default) searchAfter(ctx context.Context, filter interface{}, pagination *db.Pagination, fields db.SortParameters, results interface{}) (int, error) {
+
+	// Start after cursor position
+	if after := pagination.After(); len(after) > 0 {
+		c, err := db.KeysetCriterion(fields, after)
+		if err != nil {
+			return 0, err
+		}
+		keyset, err := convertCriterion(c)
+		if err != nil {
+			return 0, err
+		}
+		filter = bson.M{"$and": bson.A{filter, keyset}}
+	}
+
+	// Apply sorts
+	opts := options.Find().SetSort(ConvertSortParameters(fields)).SetLimit(int64(pagination.PerPage) + 1)
+
+	// Do the query
+	cur, err := d.session.Database(d.db).Collection(d.table).Find(ctx, filter, opts)
+	if err != nil {
+		return 0, wrapError(err, "mongodb: unable to query collection")
+	}
+
+	// Extract all entities
+	if err := cur.All(ctx, results); err != nil {
+		return 0, wrapError(err, "mongodb: unable to extract entities")
+	}
+
+	// Truncate extra element
+	items := reflect.Indirect(reflect.ValueOf(results))
+	hasNext := items.Len() > int(pagination.PerPage)
+	if hasNext {
+		items.Set(items.Slice(0, int(pagination.PerPage)))
+	}
+	if items.Len() == 0 {
+		if len(pagination.After()) == 0 {
+			return 0, db.ErrNoResult
+		}
+		return 0, pagination.SetNext(nil, false)
+	}
+
+	// Extract last element sort key values
+	raw, err := bson.Marshal(items.Index(items.Len() - 1).Interface())
+	if err != nil {
+		return 0, fmt.Errorf("mongodb: unable to encode last document: %w", err)
+	}
+	values := make([]interface{}, len(fields))
+	for i, f := range fields {
+		rv, err := bson.Raw(raw).LookupErr(strings.Split(f.Field, ".")...)
+		if err != nil {
+			return 0, fmt.Errorf("mongodb: unable to extract sort key '%s': %w", f.Field, err)
+		}
+		if err := rv.Unmarshal(&values[i]); err != nil {
+			return 0, fmt.Errorf("mongodb: unable to decode sort key '%s': %w", f.Field, err)
+		}
+	}
+
+	return items.Len(), pagination.SetNext(values, hasNext)
+}
+
+// noModification returns the error raised when an update matched nothing, a
+// version conflict is raised if the filter matches without expected version.
+func (d *Default) noModification(ctx context.Context, filter, expected interface{}) error {
+	if d.versionField == "" {
+		return db.ErrNoModification
+	}
+
+	count, err := d.WhereCount(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("mongodb: unable to check version conflict: %w", err)
+	}
+	if count > 0 {
+		return db.VersionConflict(d.table, expected)
+	}
+
+	return db.ErrNoModification
+}
+
+// sorts checks the given sort parameters against sortable fields, and adds
+// the primary key as tie-breaker.
+func (d *Default) sorts(params *db.SortParameters) (db.SortParameters, error) {
+	if params == nil {
+		params = &db.SortParameters{}
+	}
+
+	// Check sortable fields
+	if err := params.Validate(func(field string) bool {
+		return len(d.sortableFields) == 0 || field == d.primaryKey || d.sortableFields[field]
+	}); err != nil {
+		return nil, err
+	}
+
+	return params.TieBreak(d.primaryKey), nil
+}
+
+// where converts backend-neutral criteria to mongodb filter, other filters are
+// used as is.
+func (d *Default) where(filter interface{}) (interface{}, error) {
+	switch f := filter.(type) {
+	case nil:
+		filter = bson.M{}
+	case *db.Criterion:
+		var err error
+		if filter, err = ConvertCriteria(f, d.filterableFields); err != nil {
+			return nil, err
+		}
+	default:
+	}
+
+	// Exclude deleted documents
+	if d.tracking.SoftDelete() {
+		return bson.M{"$and": bson.A{filter, bson.M{d.tracking.DeletedAt: nil}}}, nil
+	}
+
+	return filter, nil
+}
+
+// track returns the given document with tracking fields set.
+func (d *Default) track(data interface{}, values map[string]interface{}) (interface{}, error) {
+	if len(values) == 0 {
+		return data, nil
+	}
+
+	// Convert to a generic document
+	raw, err := bson.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("mongodb: unable to encode document: %w", err)
+	}
+	doc := bson.M{}
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("mongodb: unable to decode document: %w", err)
+	}
+
+	for k, v := range values {
+		doc[k] = v
+	}
+
+	return doc, nil
 }
 
 // -----------------------------------------------------------------------------
@@ -275,13 +605,13 @@ func Transaction(ctx context.Context, client *mongo.Client, fn TransactionFunc)
 	return client.UseSession(ctx, func(sctx mongo.SessionContext) error {
 		// Start transaction
 		if err := sctx.StartTransaction(); err != nil {
-			return fmt.Errorf("mongodb: %w", err)
+			return wrapError(err, "mongodb: unable to start transaction")
 		}
 
 		// Run the closure
 		if err := fn(); err != nil {
 			log.CheckErrCtx(sctx, "Unable to abort transaction", sctx.AbortTransaction(sctx))
-			return fmt.Errorf("mongodb: %w", err)
+			return wrapError(err, "mongodb: transaction aborted")
 		}
 
 		for {
@@ -295,7 +625,7 @@ func Transaction(ctx context.Context, client *mongo.Client, fn TransactionFunc)
 				log.For(ctx).Warn("Transient error occurred, retrying transaction ...")
 				continue
 			}
-			return err
+			return wrapError(err, "mongodb: unable to commit transaction")
 		}
 	})
 }
@@ -0,0 +1,105 @@
+package mongodb
+
+import (
+	"fmt"
+
+	"go.zenithar.org/pkg/db"
+	"go.zenithar.org/pkg/errors"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+	"golang.org/x/xerrors"
+)
+
+// ErrorCode returns the code of the given error, write and command errors are
+// classified using their server error code.
+func ErrorCode(err error) errors.ErrorCode {
+	return db.ErrorCode(err, classify)
+}
+
+// wrapError returns the given error wrapped with the message and classified,
+// driver no result errors are replaced by db.ErrNoResult.
+func wrapError(err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	if xerrors.Is(err, mongo.ErrNoDocuments) {
+		err = db.ErrNoResult
+	}
+
+	return errors.New(ErrorCode(err), err, 2, fmt.Sprintf(format, args...))
+}
+
+// -----------------------------------------------------------------------------
+
+func classify(err error) errors.ErrorCode {
+	var (
+		writeErr   mongo.WriteException
+		bulkErr    mongo.BulkWriteException
+		commandErr mongo.CommandError
+	)
+
+	switch {
+	case xerrors.Is(err, mongo.ErrNoDocuments):
+		return errors.NotFound
+	case xerrors.Is(err, mongo.ErrClientDisconnected), xerrors.Is(err, topology.ErrServerSelectionTimeout):
+		return errors.Unavailable
+	case xerrors.As(err, &writeErr):
+		if len(writeErr.WriteErrors) > 0 {
+			return serverCode(writeErr.WriteErrors[0].Code)
+		}
+		if writeErr.WriteConcernError != nil {
+			return serverCode(writeErr.WriteConcernError.Code)
+		}
+	case xerrors.As(err, &bulkErr):
+		if len(bulkErr.WriteErrors) > 0 {
+			return serverCode(bulkErr.WriteErrors[0].Code)
+		}
+		if bulkErr.WriteConcernError != nil {
+			return serverCode(bulkErr.WriteConcernError.Code)
+		}
+	case xerrors.As(err, &commandErr):
+		switch {
+		case commandErr.HasErrorLabel("TransientTransactionError"):
+			return errors.Aborted
+		case commandErr.HasErrorLabel("NetworkError"):
+			return errors.Unavailable
+		}
+		return serverCode(int(commandErr.Code))
+	}
+
+	return errors.Unknown
+}
+
+// serverCode converts MongoDB server error codes.
+func serverCode(code int) errors.ErrorCode {
+	switch code {
+	case 11000, 11001, 12582:
+		// Duplicate key
+		return errors.AlreadyExists
+	case 121:
+		// Document validation failure
+		return errors.FailedPrecondition
+	case 112, 251:
+		// Write conflict and aborted transaction
+		return errors.Aborted
+	case 50, 89, 64:
+		// Time limit, network and write concern timeouts
+		return errors.DeadlineExceeded
+	case 6, 7, 91, 189, 10107, 11600, 11602, 13435, 13436:
+		// Unreachable hosts, shutdowns and primary step downs
+		return errors.Unavailable
+	case 13:
+		return errors.PermissionDenied
+	case 18:
+		return errors.Unauthenticated
+	case 26:
+		// Namespace not found
+		return errors.NotFound
+	case 2, 9, 14:
+		// Bad value, failed to parse and type mismatch
+		return errors.InvalidArgument
+	}
+
+	return errors.Internal
+}
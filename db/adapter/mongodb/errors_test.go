@@ -0,0 +1,57 @@
+package mongodb
+
+import (
+	"fmt"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/xerrors"
+
+	"go.zenithar.org/pkg/db"
+	"go.zenithar.org/pkg/errors"
+)
+
+func TestErrorCode(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want errors.ErrorCode
+	}{
+		{name: "nil", err: nil, want: errors.OK},
+		{name: "generic error", err: fmt.Errorf("foo"), want: errors.Unknown},
+		{name: "no documents", err: mongo.ErrNoDocuments, want: errors.NotFound},
+		{name: "duplicate key", err: mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000}}}, want: errors.AlreadyExists},
+		{name: "bulk validation failure", err: mongo.BulkWriteException{WriteErrors: []mongo.BulkWriteError{{WriteError: mongo.WriteError{Code: 121}}}}, want: errors.FailedPrecondition},
+		{name: "max time expired", err: mongo.CommandError{Code: 50}, want: errors.DeadlineExceeded},
+		{name: "transient transaction", err: fmt.Errorf("mongodb: %w", mongo.CommandError{Code: 251, Labels: []string{"TransientTransactionError"}}), want: errors.Aborted},
+		{name: "network error", err: mongo.CommandError{Labels: []string{"NetworkError"}}, want: errors.Unavailable},
+		{name: "client disconnected", err: mongo.ErrClientDisconnected, want: errors.Unavailable},
+	}
+
+	for _, tc := range testCases {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := ErrorCode(tt.err); got != tt.want {
+				t.Fatalf("got %v, wanted %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapError(t *testing.T) {
+	if wrapError(nil, "mongodb: unable to retrieve document") != nil {
+		t.Fatalf("error must not be raised")
+	}
+
+	err := wrapError(mongo.ErrNoDocuments, "mongodb: unable to retrieve document")
+
+	var e *errors.Error
+	if !xerrors.As(err, &e) || e.Code != errors.NotFound {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+	if !xerrors.Is(err, db.ErrNoResult) {
+		t.Fatalf("error must match db.ErrNoResult")
+	}
+}
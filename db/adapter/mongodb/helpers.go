@@ -5,18 +5,16 @@ import (
 	"go.zenithar.org/pkg/db"
 )
 
-// ConvertSortParameters to sql query string
-func ConvertSortParameters(params db.SortParameters) bson.M {
+// ConvertSortParameters to mongodb sort document, sort order is preserved.
+func ConvertSortParameters(params db.SortParameters) bson.D {
 
-	sorts := bson.M{}
-	for k, v := range params {
-		switch v {
-		case db.Ascending:
-			sorts[k] = 1
+	sorts := bson.D{}
+	for _, f := range params {
+		switch f.Direction {
 		case db.Descending:
-			sorts[k] = -1
+			sorts = append(sorts, bson.E{Key: f.Field, Value: -1})
 		default:
-			sorts[k] = 1
+			sorts = append(sorts, bson.E{Key: f.Field, Value: 1})
 		}
 	}
 
@@ -0,0 +1,63 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"go.zenithar.org/pkg/db"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var _ db.Iterable = (*Default)(nil)
+
+// Iterate returns an iterator over documents matching the given filter,
+// documents are streamed from the database.
+func (d *Default) Iterate(ctx context.Context, filter interface{}, sortParams *db.SortParameters) (db.Iterator, error) {
+	// Apply Filter
+	query, err := d.where(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check sort parameters
+	sorts, err := d.sorts(sortParams)
+	if err != nil {
+		return nil, err
+	}
+
+	// Do the query
+	cur, err := d.session.Database(d.db).Collection(d.table).Find(ctx, query, options.Find().SetSort(ConvertSortParameters(sorts)))
+	if err != nil {
+		return nil, wrapError(err, "mongodb: unable to query collection")
+	}
+
+	return &iterator{ctx: ctx, cur: cur}, nil
+}
+
+// -----------------------------------------------------------------------------
+
+type iterator struct {
+	ctx context.Context
+	cur *mongo.Cursor
+}
+
+func (it *iterator) Next() bool {
+	return it.cur.Next(it.ctx)
+}
+
+func (it *iterator) Scan(dest interface{}) error {
+	if err := it.cur.Decode(dest); err != nil {
+		return fmt.Errorf("mongodb: unable to decode document: %w", err)
+	}
+	return nil
+}
+
+func (it *iterator) Err() error {
+	return it.cur.Err()
+}
+
+func (it *iterator) Close() error {
+	return it.cur.Close(it.ctx)
+}
@@ -0,0 +1,231 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.zenithar.org/pkg/db/migrations"
+	"go.zenithar.org/pkg/log"
+
+	"github.com/dchest/uniuri"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const (
+	migrationLockID = "lock"
+	// migrationLockTTL is the delay after which a lock not refreshed by its
+	// owner is considered stale, e.g. left by a crashed process.
+	migrationLockTTL = 5 * time.Minute
+)
+
+// AutoMigrate applies pending migrations of the given set when enabled by the
+// configuration.
+func AutoMigrate(ctx context.Context, cfg *Configuration, client *mongo.Client, set *migrations.Set) error {
+	if !cfg.AutoMigrate {
+		return nil
+	}
+
+	databaseName, err := cfg.database()
+	if err != nil {
+		return err
+	}
+
+	count, err := migrations.New(MigrationDriver(client, databaseName, "schema_migrations"), set).Up(ctx, 0)
+	if err != nil {
+		return fmt.Errorf("mongodb: unable to apply migrations: %w", err)
+	}
+	if count > 0 {
+		log.For(ctx).Info("MongoDB schema migrated.")
+	}
+
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+type migrationRecord struct {
+	Version     int64     `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"appliedAt"`
+}
+
+type migrationDriver struct {
+	database   *mongo.Database
+	collection string
+	owner      string
+	release    chan struct{}
+}
+
+// MigrationDriver returns a migration driver persisting applied versions in
+// the given collection, a lock document prevents concurrent runs. The lock is
+// refreshed while held, and taken over when not refreshed during its TTL.
+// Migration steps are executed with a *mongo.Database session.
+func MigrationDriver(client *mongo.Client, db, collection string) migrations.Driver {
+	return &migrationDriver{
+		database:   client.Database(db),
+		collection: collection,
+		owner:      uniuri.New(),
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *migrationDriver) Lock(ctx context.Context) error {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		now := time.Now().UTC()
+
+		// Lock is acquired by the first lock document insertion
+		_, err := d.database.Collection(d.collection).InsertOne(ctx, bson.M{
+			"_id":      migrationLockID,
+			"owner":    d.owner,
+			"lockedAt": now,
+		})
+		if err == nil {
+			d.keepAlive()
+			return nil
+		}
+		if !isDuplicateKey(err) {
+			return fmt.Errorf("mongodb: unable to acquire migration lock: %w", err)
+		}
+
+		// Take over stale lock
+		res, err := d.database.Collection(d.collection).UpdateOne(ctx, bson.M{
+			"_id":      migrationLockID,
+			"lockedAt": bson.M{"$lt": now.Add(-migrationLockTTL)},
+		}, bson.M{
+			"$set": bson.M{"owner": d.owner, "lockedAt": now},
+		})
+		if err != nil {
+			return fmt.Errorf("mongodb: unable to acquire migration lock: %w", err)
+		}
+		if res.ModifiedCount > 0 {
+			log.For(ctx).Warn("Stale migration lock taken over", zap.Duration("ttl", migrationLockTTL))
+			d.keepAlive()
+			return nil
+		}
+
+		// Wait for lock release
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("mongodb: unable to acquire migration lock: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *migrationDriver) Unlock(ctx context.Context) error {
+	// Stop lock refresh
+	if d.release != nil {
+		close(d.release)
+		d.release = nil
+	}
+
+	if _, err := d.database.Collection(d.collection).DeleteOne(ctx, bson.M{"_id": migrationLockID, "owner": d.owner}); err != nil {
+		return fmt.Errorf("mongodb: unable to release migration lock: %w", err)
+	}
+	return nil
+}
+
+func (d *migrationDriver) Applied(ctx context.Context) ([]migrations.Record, error) {
+	cur, err := d.database.Collection(d.collection).Find(ctx, bson.M{
+		"_id": bson.M{"$type": "long"},
+	}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("mongodb: unable to retrieve applied versions: %w", err)
+	}
+
+	var records []migrationRecord
+	if err := cur.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("mongodb: unable to decode applied versions: %w", err)
+	}
+
+	res := make([]migrations.Record, len(records))
+	for i, r := range records {
+		res[i] = migrations.Record{
+			Version:     uint64(r.Version),
+			Description: r.Description,
+			AppliedAt:   r.AppliedAt,
+		}
+	}
+
+	return res, nil
+}
+
+func (d *migrationDriver) Apply(ctx context.Context, m *migrations.Migration, direction migrations.Direction) error {
+	collection := d.database.Collection(d.collection)
+
+	// Execute migration step and update version collection
+	switch direction {
+	case migrations.Up:
+		if err := m.Up(ctx, d.database); err != nil {
+			return err
+		}
+		if _, err := collection.InsertOne(ctx, &migrationRecord{
+			Version:     int64(m.Version),
+			Description: m.Description,
+			AppliedAt:   time.Now().UTC(),
+		}); err != nil {
+			return fmt.Errorf("mongodb: unable to update version collection: %w", err)
+		}
+	case migrations.Down:
+		if err := m.Down(ctx, d.database); err != nil {
+			return err
+		}
+		if _, err := collection.DeleteOne(ctx, bson.M{"_id": int64(m.Version)}); err != nil {
+			return fmt.Errorf("mongodb: unable to update version collection: %w", err)
+		}
+	default:
+		return fmt.Errorf("mongodb: unsupported migration direction '%d'", direction)
+	}
+
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+// keepAlive refreshes the lock document until the lock is released, so that
+// long running migrations are not considered stale.
+func (d *migrationDriver) keepAlive() {
+	release := make(chan struct{})
+	d.release = release
+
+	go func() {
+		ticker := time.NewTicker(migrationLockTTL / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-release:
+				return
+			case <-ticker.C:
+				_, err := d.database.Collection(d.collection).UpdateOne(context.Background(), bson.M{
+					"_id":   migrationLockID,
+					"owner": d.owner,
+				}, bson.M{
+					"$set": bson.M{"lockedAt": time.Now().UTC()},
+				})
+				log.CheckErr("Unable to refresh migration lock", err)
+			}
+		}
+	}()
+}
+
+func isDuplicateKey(err error) bool {
+	var we mongo.WriteException
+	if errors.As(err, &we) {
+		for _, e := range we.WriteErrors {
+			if e.Code == 11000 {
+				return true
+			}
+		}
+	}
+	return false
+}
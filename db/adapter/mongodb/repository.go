@@ -0,0 +1,46 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.zenithar.org/pkg/db"
+)
+
+var _ db.Repository = (*repository)(nil)
+
+// Repository returns the collection as a db.Repository, Default methods keep
+// their historical signatures.
+func (d *Default) Repository() db.Repository {
+	return &repository{d: d}
+}
+
+// -----------------------------------------------------------------------------
+
+type repository struct {
+	d *Default
+}
+
+func (r *repository) Create(ctx context.Context, data interface{}) error {
+	return r.d.Create(ctx, data)
+}
+
+func (r *repository) WhereCount(ctx context.Context, filter interface{}) (int, error) {
+	count, err := r.d.WhereCount(ctx, filter)
+	return int(count), err
+}
+
+func (r *repository) WhereAndFetchOne(ctx context.Context, filter interface{}, result interface{}) error {
+	return r.d.WhereAndFetchOne(ctx, filter, result)
+}
+
+func (r *repository) Update(ctx context.Context, updates map[string]interface{}, filter interface{}) error {
+	return r.d.UpdateWhere(ctx, updates, filter)
+}
+
+func (r *repository) RemoveOne(ctx context.Context, filter interface{}) error {
+	return r.d.RemoveOne(ctx, filter)
+}
+
+func (r *repository) Search(ctx context.Context, filter interface{}, pagination *db.Pagination, sortParams *db.SortParameters, results interface{}) (int, error) {
+	return r.d.search(ctx, filter, pagination, sortParams, results)
+}
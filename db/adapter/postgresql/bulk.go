@@ -0,0 +1,234 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.zenithar.org/pkg/db/adapter/internal/sqlcrud"
+	"go.zenithar.org/pkg/errors"
+	"go.zenithar.org/pkg/log"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/stdlib"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+	"golang.org/x/xerrors"
+)
+
+// maxParameters is the PostgreSQL bind parameter limit of a statement.
+const maxParameters = 65535
+
+// CreateMany inserts all elements of the given slice using multi-row inserts,
+// in a single transaction.
+func (d *Default) CreateMany(ctx context.Context, items interface{}) error {
+	// Extract rows
+	columns, rows, err := d.extractRows(ctx, items)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	// Split in batches according to parameter limit
+	batchSize := maxParameters / len(columns)
+
+	return WithTx(ctx, d.session, func(ctx context.Context) error {
+		for start := 0; start < len(rows); start += batchSize {
+			end := start + batchSize
+			if end > len(rows) {
+				end = len(rows)
+			}
+
+			// Prepare query
+			qb := sq.Insert(d.table).
+				Columns(columns...).
+				PlaceholderFormat(sq.Dollar)
+			for _, values := range rows[start:end] {
+				qb = qb.Values(values...)
+			}
+
+			// Build sql query
+			q, args, err := qb.ToSql()
+			if err != nil {
+				return fmt.Errorf("postgresql: unable to build query: %w", err)
+			}
+
+			// Do the insert query
+			if _, err := d.executor(ctx).ExecContext(ctx, q, args...); err != nil {
+				return wrapError(err, "postgresql: unable to execute query")
+			}
+		}
+
+		return nil
+	})
+}
+
+// Upsert inserts the given element, or updates the given columns of the row
+// conflicting on conflict columns. Conflicting rows are left untouched when no
+// update column is given.
+func (d *Default) Upsert(ctx context.Context, data interface{}, conflictColumns, updateColumns []string) error {
+	// Check arguments
+	if len(conflictColumns) == 0 {
+		return errors.Newf(errors.InvalidArgument, nil, "postgresql(%s): upsert requires at least one conflict column", d.table)
+	}
+
+	// Extract columns and values
+	now := time.Now().UTC()
+	columns, values := d.crud.ExtractColumnPairs(data)
+	columns, values = sqlcrud.MergeColumnPairs(columns, values, d.crud.Tracking().OnCreate(ctx, now))
+
+	known := toSet(columns)
+	for _, column := range conflictColumns {
+		if !known[column] {
+			return errors.Newf(errors.InvalidArgument, nil, "postgresql(%s): unknown conflict column '%s'", d.table, column)
+		}
+	}
+
+	// Build conflict clause
+	conflict := "DO NOTHING"
+	if len(updateColumns) > 0 {
+		// Refresh tracking columns
+		for column := range d.crud.Tracking().OnUpdate(ctx, now) {
+			updateColumns = append(updateColumns, column)
+		}
+
+		sets := make([]string, 0, len(updateColumns))
+		seen := map[string]bool{}
+		for _, column := range updateColumns {
+			if !known[column] {
+				return errors.Newf(errors.InvalidArgument, nil, "postgresql(%s): unknown update column '%s'", d.table, column)
+			}
+			if seen[column] {
+				continue
+			}
+			seen[column] = true
+			sets = append(sets, fmt.Sprintf("%s = EXCLUDED.%s", column, column))
+		}
+		conflict = fmt.Sprintf("DO UPDATE SET %s", strings.Join(sets, ", "))
+	}
+
+	// Prepare query
+	qb := sq.Insert(d.table).
+		Columns(columns...).
+		Values(values...).
+		Suffix(fmt.Sprintf("ON CONFLICT (%s) %s", strings.Join(conflictColumns, ", "), conflict)).
+		PlaceholderFormat(sq.Dollar)
+
+	// Build sql query
+	q, args, err := qb.ToSql()
+	if err != nil {
+		return fmt.Errorf("postgresql: unable to build query: %w", err)
+	}
+
+	// Do the upsert query
+	if _, err := d.executor(ctx).ExecContext(ctx, q, args...); err != nil {
+		return wrapError(err, "postgresql: unable to execute query")
+	}
+
+	return nil
+}
+
+// CopyFrom inserts all elements of the given slice using the COPY protocol,
+// and returns the copied row count. The pgx native protocol is used outside of
+// transactions, lib/pq COPY statements are used otherwise.
+func (d *Default) CopyFrom(ctx context.Context, items interface{}) (int64, error) {
+	// Extract rows
+	columns, rows, err := d.extractRows(ctx, items)
+	if err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	// Use pgx native protocol
+	if TxFromContext(ctx) == nil {
+		conn, err := stdlib.AcquireConn(d.session.DB)
+		switch {
+		case err == nil:
+			defer func() {
+				if err := stdlib.ReleaseConn(d.session.DB, conn); err != nil {
+					log.For(ctx).Error("Unable to release connection", zap.Error(err))
+				}
+			}()
+
+			count, err := conn.CopyFrom(ctx, pgx.Identifier(strings.Split(d.table, ".")), columns, pgx.CopyFromRows(rows))
+			if err != nil {
+				return 0, wrapError(err, "postgresql: unable to copy rows")
+			}
+			return count, nil
+		case xerrors.Is(err, stdlib.ErrNotPgx):
+			// Fallback to COPY statements
+		default:
+			return 0, wrapError(err, "postgresql: unable to acquire connection")
+		}
+	}
+
+	// Use COPY statements
+	err = WithTx(ctx, d.session, func(ctx context.Context) error {
+		stmt, err := TxFromContext(ctx).PreparexContext(ctx, pq.CopyIn(d.table, columns...))
+		if err != nil {
+			return wrapError(err, "postgresql: unable to prepare copy statement")
+		}
+		defer func(stmt *sqlx.Stmt) {
+			log.SafeClose(stmt, "Unable to close statement")
+		}(stmt)
+
+		for _, values := range rows {
+			if _, err := stmt.ExecContext(ctx, values...); err != nil {
+				return wrapError(err, "postgresql: unable to copy row")
+			}
+		}
+
+		// Flush buffered rows
+		if _, err := stmt.ExecContext(ctx); err != nil {
+			return wrapError(err, "postgresql: unable to copy rows")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(rows)), nil
+}
+
+// -----------------------------------------------------------------------------
+
+// extractRows returns the shared columns and values of all slice elements.
+func (d *Default) extractRows(ctx context.Context, items interface{}) ([]string, [][]interface{}, error) {
+	v := reflect.Indirect(reflect.ValueOf(items))
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, nil, errors.Newf(errors.InvalidArgument, nil, "postgresql(%s): items must be a slice, got '%T'", d.table, items)
+	}
+
+	var columns []string
+	rows := make([][]interface{}, 0, v.Len())
+	tracking := d.crud.Tracking().OnCreate(ctx, time.Now().UTC())
+
+	for i := 0; i < v.Len(); i++ {
+		cols, values := d.crud.ExtractColumnPairs(reflect.Indirect(v.Index(i)).Interface())
+		cols, values = sqlcrud.MergeColumnPairs(cols, values, tracking)
+
+		// Check column consistency
+		if i == 0 {
+			columns = cols
+		} else if !reflect.DeepEqual(cols, columns) {
+			return nil, nil, errors.Newf(errors.InvalidArgument, nil, "postgresql(%s): item %d columns differ from first item", d.table, i)
+		}
+		if len(columns) == 0 {
+			return nil, nil, errors.Newf(errors.InvalidArgument, nil, "postgresql(%s): item %d has no column", d.table, i)
+		}
+
+		rows = append(rows, values)
+	}
+
+	return columns, rows, nil
+}
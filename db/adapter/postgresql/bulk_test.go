@@ -0,0 +1,88 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type bulkUser struct {
+	ID        string    `db:"id"`
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func TestDefault_ExtractRows(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		opts        []Option
+		items       interface{}
+		wantColumns []string
+		wantRows    int
+		wantErr     bool
+	}{
+		{
+			name:    "not a slice",
+			items:   &bulkUser{},
+			wantErr: true,
+		},
+		{
+			name:        "empty slice",
+			items:       []bulkUser{},
+			wantColumns: nil,
+			wantRows:    0,
+		},
+		{
+			name:        "values",
+			items:       []bulkUser{{ID: "1"}, {ID: "2"}},
+			wantColumns: []string{"created_at", "id", "name"},
+			wantRows:    2,
+		},
+		{
+			name:        "pointers with tracking",
+			opts:        []Option{WithTimestamps("created_at", "updated_at")},
+			items:       []*bulkUser{{ID: "1"}, {ID: "2"}, {ID: "3"}},
+			wantColumns: []string{"created_at", "id", "name", "updated_at"},
+			wantRows:    3,
+		},
+		{
+			name:    "mixed types",
+			items:   []interface{}{&bulkUser{ID: "1"}, &struct{ ID string }{ID: "2"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			underTest := NewCRUDTable(nil, "db", "users", []string{"id", "name", "created_at"}, nil, tt.opts...)
+
+			columns, rows, err := underTest.extractRows(context.Background(), tt.items)
+			if tt.wantErr && err == nil {
+				t.Fatalf("expected error mst be raised")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("error must not be raised, got %v", err)
+			}
+			if err != nil {
+				return
+			}
+			if !cmp.Equal(columns, tt.wantColumns) {
+				t.Fatalf("got %v, wanted %v", columns, tt.wantColumns)
+			}
+			if len(rows) != tt.wantRows {
+				t.Fatalf("got %d rows, wanted %d", len(rows), tt.wantRows)
+			}
+			for _, values := range rows {
+				if len(values) != len(columns) {
+					t.Fatalf("got %d values, wanted %d", len(values), len(columns))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package postgresql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.zenithar.org/pkg/db"
+	"go.zenithar.org/pkg/errors"
+	"go.zenithar.org/pkg/log"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const (
+	changeBatchSize = 100
+	// changePendingInterval is the polling delay used while changes are held
+	// back by running transactions.
+	changePendingInterval = time.Second
+)
+
+// ChangeFeedSchema returns the statements creating the change log table and
+// the trigger recording changes of the given table. Each change is appended to
+// the '<table>_changes' table with its transaction identifier, and its
+// identifier is notified on the channel of the same name. Identifiers are
+// quoted, the table name is case-sensitive.
+func ChangeFeedSchema(table string) string {
+	changes := table + "_changes"
+
+	// Log table is given as trigger argument, the function body doesn't
+	// depend on the table name.
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %[2]s (
+	id         BIGSERIAL PRIMARY KEY,
+	txid       BIGINT NOT NULL DEFAULT txid_current(),
+	operation  TEXT NOT NULL,
+	old_value  JSONB,
+	new_value  JSONB,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+ALTER TABLE %[2]s ADD COLUMN IF NOT EXISTS txid BIGINT NOT NULL DEFAULT txid_current();
+CREATE INDEX IF NOT EXISTS %[5]s ON %[2]s (txid, id);
+
+CREATE OR REPLACE FUNCTION %[3]s() RETURNS TRIGGER AS $$
+DECLARE
+	change_id BIGINT;
+BEGIN
+	EXECUTE format('INSERT INTO %%I (operation, old_value, new_value) VALUES ($1, $2, $3) RETURNING id', TG_ARGV[0])
+	INTO change_id
+	USING
+		TG_OP,
+		CASE WHEN TG_OP <> 'INSERT' THEN to_jsonb(OLD) END,
+		CASE WHEN TG_OP <> 'DELETE' THEN to_jsonb(NEW) END;
+	PERFORM pg_notify(TG_ARGV[0], change_id::text);
+	RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS %[3]s ON %[1]s;
+CREATE TRIGGER %[3]s AFTER INSERT OR UPDATE OR DELETE ON %[1]s
+	FOR EACH ROW EXECUTE PROCEDURE %[3]s(%[4]s);`,
+		pq.QuoteIdentifier(table),
+		pq.QuoteIdentifier(changes),
+		pq.QuoteIdentifier(table+"_notify_change"),
+		pq.QuoteLiteral(changes),
+		pq.QuoteIdentifier(changes+"_position"),
+	)
+}
+
+// PruneChanges deletes changes of the given table recorded before the given
+// time, and returns the deleted change count. The change log is not pruned
+// automatically, it must be called periodically with a retention longer than
+// the maximum consumer lag: pruned changes are not delivered to late
+// consumers.
+func PruneChanges(ctx context.Context, session *sqlx.DB, table string, before time.Time) (int64, error) {
+	// Only changes of finished transactions are pruned
+	res, err := session.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE created_at < $1 AND txid < txid_snapshot_xmin(txid_current_snapshot())", pq.QuoteIdentifier(table+"_changes")), before)
+	if err != nil {
+		return 0, wrapError(err, "postgresql: unable to prune changes")
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, wrapError(err, "postgresql: unable to retrieve query result")
+	}
+
+	return count, nil
+}
+
+// -----------------------------------------------------------------------------
+
+type changeFeed struct {
+	cfg     *Configuration
+	session *sqlx.DB
+	table   string
+}
+
+// ChangeFeed returns a change feed of the given table, changes are recorded by
+// the ChangeFeedSchema trigger and consumed using LISTEN/NOTIFY.
+//
+// Changes are ordered by transaction and delivered once all transactions
+// started before theirs are finished, so that concurrent transactions
+// committed out of order are not skipped. Long running transactions delay the
+// delivery. The resume token is the last processed transaction and change
+// identifiers, the change log must be pruned using PruneChanges.
+func ChangeFeed(cfg *Configuration, session *sqlx.DB, table string) db.ChangeFeed {
+	return &changeFeed{
+		cfg:     cfg,
+		session: session,
+		table:   table,
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func (f *changeFeed) Watch(ctx context.Context, resumeToken string, fn db.ChangeHandler) error {
+	pos, err := f.position(ctx, resumeToken)
+	if err != nil {
+		return err
+	}
+
+	// Listener uses a dedicated connection
+	connStr, err := ParseURL(f.cfg.ConnectionString)
+	if err != nil {
+		return fmt.Errorf("postgresql: %w", err)
+	}
+	delete(connStr.Options, "driver")
+	if f.cfg.Username != "" {
+		connStr.User = f.cfg.Username
+	}
+	if f.cfg.Password != "" {
+		connStr.Password = f.cfg.Password
+	}
+	if f.cfg.UseTLS {
+		if err := pqTLS(f.cfg, &connStr); err != nil {
+			return err
+		}
+	}
+
+	listener := pq.NewListener(connStr.String(), 10*time.Second, time.Minute, nil)
+	defer log.SafeClose(listener, "Unable to close listener")
+
+	// Channel name is quoted by the listener
+	if err := listener.Listen(f.table + "_changes"); err != nil {
+		return wrapError(err, "postgresql: unable to listen change notifications")
+	}
+
+	for {
+		// Notifications are only used as wake-up signals, changes are read
+		// from the log to recover the ones missed during reconnections.
+		var pending bool
+		pos, pending, err = f.fetch(ctx, pos, fn)
+		if err != nil {
+			return err
+		}
+
+		// Poll while changes are held back, transactions finished without
+		// changes are not notified.
+		wait := time.Minute
+		if pending {
+			wait = changePendingInterval
+		}
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-listener.Notify:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func (f *changeFeed) position(ctx context.Context, resumeToken string) (changePosition, error) {
+	if resumeToken != "" {
+		return parseChangePosition(resumeToken)
+	}
+
+	// Start from now, changes of running transactions are delivered
+	var xmin int64
+	if err := f.session.GetContext(ctx, &xmin, "SELECT txid_snapshot_xmin(txid_current_snapshot())"); err != nil {
+		return changePosition{}, wrapError(err, "postgresql: unable to retrieve transaction horizon")
+	}
+
+	return changePosition{TxID: xmin - 1, ID: math.MaxInt64}, nil
+}
+
+// fetch delivers changes after the given position, in transaction order. It
+// stops at the first change of a transaction not yet finished or preceded by
+// a running one, and returns true if such change is pending.
+func (f *changeFeed) fetch(ctx context.Context, pos changePosition, fn db.ChangeHandler) (changePosition, bool, error) {
+	query := fmt.Sprintf(`SELECT id, txid, operation, old_value, new_value, txid < txid_snapshot_xmin(txid_current_snapshot()) AS final
+FROM %s WHERE (txid, id) > ($1, $2) ORDER BY txid, id LIMIT %d`, pq.QuoteIdentifier(f.table+"_changes"), changeBatchSize)
+
+	for {
+		var records []changeRecord
+		if err := f.session.SelectContext(ctx, &records, query, pos.TxID, pos.ID); err != nil {
+			return pos, false, wrapError(err, "postgresql: unable to retrieve changes")
+		}
+
+		for i := range records {
+			if !records[i].Final {
+				return pos, true, nil
+			}
+
+			change, err := records[i].decode(f.table)
+			if err != nil {
+				return pos, false, err
+			}
+			if err := fn(ctx, change); err != nil {
+				return pos, false, err
+			}
+			pos = changePosition{TxID: records[i].TxID, ID: records[i].ID}
+		}
+
+		if len(records) < changeBatchSize {
+			return pos, false, nil
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// changePosition identifies a change in transaction order.
+type changePosition struct {
+	TxID int64
+	ID   int64
+}
+
+func parseChangePosition(token string) (changePosition, error) {
+	parts := strings.SplitN(token, ":", 2)
+	if len(parts) != 2 {
+		return changePosition{}, errors.Newf(errors.InvalidArgument, nil, "postgresql: invalid resume token")
+	}
+
+	txID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return changePosition{}, errors.Newf(errors.InvalidArgument, err, "postgresql: invalid resume token")
+	}
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return changePosition{}, errors.Newf(errors.InvalidArgument, err, "postgresql: invalid resume token")
+	}
+
+	return changePosition{TxID: txID, ID: id}, nil
+}
+
+func (p changePosition) String() string {
+	return fmt.Sprintf("%d:%d", p.TxID, p.ID)
+}
+
+// -----------------------------------------------------------------------------
+
+type changeRecord struct {
+	ID        int64  `db:"id"`
+	TxID      int64  `db:"txid"`
+	Final     bool   `db:"final"`
+	Operation string `db:"operation"`
+	OldValue  []byte `db:"old_value"`
+	NewValue  []byte `db:"new_value"`
+}
+
+func (r *changeRecord) decode(table string) (*db.Change, error) {
+	change := &db.Change{
+		Table:       table,
+		ResumeToken: changePosition{TxID: r.TxID, ID: r.ID}.String(),
+	}
+
+	switch r.Operation {
+	case "INSERT":
+		change.Type = db.ChangeInsert
+	case "UPDATE":
+		change.Type = db.ChangeUpdate
+	case "DELETE":
+		change.Type = db.ChangeDelete
+	default:
+		return nil, errors.Newf(errors.Internal, nil, "postgresql: unsupported change operation '%s'", r.Operation)
+	}
+
+	if len(r.OldValue) > 0 {
+		if err := json.Unmarshal(r.OldValue, &change.Old); err != nil {
+			return nil, fmt.Errorf("postgresql: unable to decode old value: %w", err)
+		}
+	}
+	if len(r.NewValue) > 0 {
+		if err := json.Unmarshal(r.NewValue, &change.New); err != nil {
+			return nil, fmt.Errorf("postgresql: unable to decode new value: %w", err)
+		}
+	}
+
+	return change, nil
+}
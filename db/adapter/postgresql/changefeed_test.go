@@ -0,0 +1,184 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.zenithar.org/pkg/db"
+)
+
+func TestChangeRecord_Decode(t *testing.T) {
+	testCases := []struct {
+		name    string
+		record  changeRecord
+		want    *db.Change
+		wantErr bool
+	}{
+		{
+			name:   "insert",
+			record: changeRecord{ID: 1, TxID: 10, Operation: "INSERT", NewValue: []byte(`{"id":"1","name":"foo"}`)},
+			want: &db.Change{
+				Type:        db.ChangeInsert,
+				Table:       "users",
+				New:         map[string]interface{}{"id": "1", "name": "foo"},
+				ResumeToken: "10:1",
+			},
+		},
+		{
+			name:   "update",
+			record: changeRecord{ID: 2, TxID: 11, Operation: "UPDATE", OldValue: []byte(`{"name":"foo"}`), NewValue: []byte(`{"name":"bar"}`)},
+			want: &db.Change{
+				Type:        db.ChangeUpdate,
+				Table:       "users",
+				Old:         map[string]interface{}{"name": "foo"},
+				New:         map[string]interface{}{"name": "bar"},
+				ResumeToken: "11:2",
+			},
+		},
+		{
+			name:   "delete",
+			record: changeRecord{ID: 3, TxID: 12, Operation: "DELETE", OldValue: []byte(`{"name":"bar"}`)},
+			want: &db.Change{
+				Type:        db.ChangeDelete,
+				Table:       "users",
+				Old:         map[string]interface{}{"name": "bar"},
+				ResumeToken: "12:3",
+			},
+		},
+		{
+			name:    "unsupported operation",
+			record:  changeRecord{ID: 4, Operation: "TRUNCATE"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid value",
+			record:  changeRecord{ID: 5, Operation: "INSERT", NewValue: []byte(`{`)},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.record.decode("users")
+			if tt.wantErr && err == nil {
+				t.Fatalf("expected error mst be raised")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("error must not be raised, got %v", err)
+			}
+			if !cmp.Equal(got, tt.want) {
+				t.Fatalf("got %v, wanted %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChangeFeedSchema(t *testing.T) {
+	schema := ChangeFeedSchema(`users"; DROP TABLE accounts; --`)
+
+	for _, want := range []string{
+		`CREATE TABLE IF NOT EXISTS "users""; DROP TABLE accounts; --_changes"`,
+		`CREATE INDEX IF NOT EXISTS "users""; DROP TABLE accounts; --_changes_position" ON "users""; DROP TABLE accounts; --_changes" (txid, id)`,
+		`ON "users""; DROP TABLE accounts; --"`,
+		`EXECUTE PROCEDURE "users""; DROP TABLE accounts; --_notify_change"('users"; DROP TABLE accounts; --_changes')`,
+	} {
+		if !strings.Contains(schema, want) {
+			t.Fatalf("schema must contain %q, got %s", want, schema)
+		}
+	}
+	if body := schema[strings.Index(schema, "AS $$"):strings.Index(schema, "$$ LANGUAGE")]; strings.Contains(body, "users") {
+		t.Fatalf("function body must not depend on the table name, got %s", body)
+	}
+}
+
+func TestParseChangePosition(t *testing.T) {
+	testCases := []struct {
+		name    string
+		token   string
+		want    changePosition
+		wantErr bool
+	}{
+		{name: "valid", token: "12:3", want: changePosition{TxID: 12, ID: 3}},
+		{name: "legacy identifier", token: "3", wantErr: true},
+		{name: "invalid transaction", token: "a:3", wantErr: true},
+		{name: "invalid identifier", token: "12:b", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseChangePosition(tt.token)
+			if tt.wantErr && err == nil {
+				t.Fatalf("expected error mst be raised")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("error must not be raised, got %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("got %v, wanted %v", got, tt.want)
+			}
+			if !tt.wantErr && got.String() != tt.token {
+				t.Fatalf("got %q, wanted %q", got.String(), tt.token)
+			}
+		})
+	}
+}
+
+func TestChangeFeed_Fetch(t *testing.T) {
+	session, j := fakeDB(t)
+	j.respond = func(query string) ([]string, [][]driver.Value) {
+		// Transaction 11 is still running, transaction 12 committed first
+		return []string{"id", "txid", "final", "operation", "old_value", "new_value"}, [][]driver.Value{
+			{int64(1), int64(10), true, "INSERT", nil, []byte(`{"name":"foo"}`)},
+			{int64(3), int64(11), false, "INSERT", nil, []byte(`{"name":"bar"}`)},
+			{int64(2), int64(12), true, "INSERT", nil, []byte(`{"name":"baz"}`)},
+		}
+	}
+
+	f := &changeFeed{session: session, table: "users"}
+
+	var tokens []string
+	pos, pending, err := f.fetch(context.Background(), changePosition{TxID: 9, ID: 0}, func(_ context.Context, change *db.Change) error {
+		tokens = append(tokens, change.ResumeToken)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if !pending {
+		t.Fatalf("changes must be pending")
+	}
+	if want := (changePosition{TxID: 10, ID: 1}); pos != want {
+		t.Fatalf("got %v, wanted %v", pos, want)
+	}
+	if want := []string{"10:1"}; !cmp.Equal(tokens, want) {
+		t.Fatalf("got %v, wanted %v", tokens, want)
+	}
+}
+
+func TestPruneChanges(t *testing.T) {
+	session, j := fakeDB(t)
+
+	count, err := PruneChanges(context.Background(), session, "users", time.Now())
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("got %d, wanted %d", count, 1)
+	}
+
+	want := []string{`DELETE FROM "users_changes" WHERE created_at < $1 AND txid < txid_snapshot_xmin(txid_current_snapshot())`}
+	if got := j.Events(); !cmp.Equal(got, want) {
+		t.Fatalf("got %v, wanted %v", got, want)
+	}
+}
@@ -0,0 +1,79 @@
+package postgresql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jmoiron/sqlx"
+	"golang.org/x/xerrors"
+
+	"go.zenithar.org/pkg/errors"
+)
+
+func TestConnector(t *testing.T) {
+	underTest := NewConnector()
+
+	// Register lazy connections, no database is reached
+	for _, name := range []string{"primary", "analytics"} {
+		conn, err := sqlx.Open("postgres", "postgres://localhost/"+name)
+		if err != nil {
+			t.Fatalf("error must not be raised, got %v", err)
+		}
+		if err := underTest.register(newDB(name, conn)); err != nil {
+			t.Fatalf("error must not be raised, got %v", err)
+		}
+	}
+
+	// Named lookups
+	got, err := underTest.Get("analytics")
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if got.Name() != "analytics" {
+		t.Fatalf("got %q, wanted %q", got.Name(), "analytics")
+	}
+	var e *errors.Error
+	if _, err := underTest.Get("unknown"); !xerrors.As(err, &e) || e.Code != errors.NotFound {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+
+	// Duplicate name
+	conn, err := sqlx.Open("postgres", "postgres://localhost/primary")
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if err := underTest.register(newDB("primary", conn)); err == nil {
+		t.Fatalf("expected error mst be raised")
+	}
+
+	// Close all handles
+	if err := underTest.Close(); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if _, err := underTest.Get("primary"); err == nil {
+		t.Fatalf("expected error mst be raised")
+	}
+	if err := got.Close(); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+}
+
+func TestWithDefaults(t *testing.T) {
+	cfg := &Configuration{MaxOpenConns: -1, MaxRetries: 3}
+
+	got := withDefaults(cfg)
+	want := &Configuration{
+		MaxOpenConns:    -1,
+		ConnMaxLifetime: 5 * time.Minute,
+		ConnectTimeout:  10 * time.Second,
+		MaxRetries:      3,
+		RetryInterval:   time.Second,
+	}
+	if !cmp.Equal(got, want) {
+		t.Fatalf("got %+v, wanted %+v", got, want)
+	}
+	if cfg.ConnMaxLifetime != 0 {
+		t.Fatalf("configuration must not be modified")
+	}
+}
@@ -0,0 +1,128 @@
+package postgresql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func init() {
+	sql.Register("postgresqltest", &fakeDriver{})
+}
+
+// journal records transaction events and statements executed through the
+// fake driver, queries return rows given by the optional responder.
+type journal struct {
+	sync.Mutex
+	events  []string
+	respond func(query string) ([]string, [][]driver.Value)
+}
+
+func (j *journal) append(event string) {
+	j.Lock()
+	j.events = append(j.events, event)
+	j.Unlock()
+}
+
+func (j *journal) Events() []string {
+	j.Lock()
+	defer j.Unlock()
+	return append([]string(nil), j.events...)
+}
+
+var journals sync.Map
+
+// fakeDB returns a database handle using the fake driver, and the journal of
+// executed operations.
+func fakeDB(t *testing.T) (*sqlx.DB, *journal) {
+	j := &journal{}
+	journals.Store(t.Name(), j)
+
+	conn, err := sqlx.Open("postgresqltest", t.Name())
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		journals.Delete(t.Name())
+	})
+
+	return conn, j
+}
+
+// -----------------------------------------------------------------------------
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	j, _ := journals.Load(name)
+	return &fakeConn{journal: j.(*journal)}, nil
+}
+
+type fakeConn struct {
+	journal *journal
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, query: query}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.journal.append("BEGIN")
+	return c, nil
+}
+
+func (c *fakeConn) Commit() error {
+	c.journal.append("COMMIT")
+	return nil
+}
+
+func (c *fakeConn) Rollback() error {
+	c.journal.append("ROLLBACK")
+	return nil
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.conn.journal.append(s.query)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.conn.journal.append(s.query)
+	if s.conn.journal.respond == nil {
+		return &fakeRows{}, nil
+	}
+	columns, rows := s.conn.journal.respond(s.query)
+	return &fakeRows{columns: columns, rows: rows}, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
@@ -0,0 +1,84 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"go.zenithar.org/pkg/db"
+	"go.zenithar.org/pkg/errors"
+
+	"github.com/lib/pq"
+	"golang.org/x/xerrors"
+)
+
+// ErrorCode returns the code of the given error, lib/pq and pgx errors are
+// classified using their SQLSTATE.
+func ErrorCode(err error) errors.ErrorCode {
+	return db.ErrorCode(err, classify)
+}
+
+// wrapError returns the given error wrapped with the message and classified,
+// driver no result errors are replaced by db.ErrNoResult.
+func wrapError(err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	if xerrors.Is(err, sql.ErrNoRows) {
+		err = db.ErrNoResult
+	}
+
+	return errors.New(ErrorCode(err), err, 2, fmt.Sprintf(format, args...))
+}
+
+// -----------------------------------------------------------------------------
+
+func classify(err error) errors.ErrorCode {
+	if xerrors.Is(err, sql.ErrNoRows) {
+		return errors.NotFound
+	}
+
+	state := sqlState(err)
+	switch {
+	case state == "":
+		return errors.Unknown
+	case state == "23505":
+		return errors.AlreadyExists
+	case state == "57014":
+		return errors.DeadlineExceeded
+	case state == "42501":
+		return errors.PermissionDenied
+	case strings.HasPrefix(state, "23"):
+		// Integrity constraint violations
+		return errors.FailedPrecondition
+	case strings.HasPrefix(state, "40"), state == "55P03":
+		// Transaction rollbacks and lock timeouts
+		return errors.Aborted
+	case strings.HasPrefix(state, "08"), strings.HasPrefix(state, "57P"):
+		// Connection exceptions and server shutdowns
+		return errors.Unavailable
+	case strings.HasPrefix(state, "53"):
+		return errors.ResourceExhausted
+	case strings.HasPrefix(state, "28"):
+		return errors.Unauthenticated
+	case strings.HasPrefix(state, "22"):
+		return errors.InvalidArgument
+	}
+
+	return errors.Internal
+}
+
+// sqlState returns the SQLSTATE of lib/pq and pgx errors.
+func sqlState(err error) string {
+	var pqErr *pq.Error
+	var pgErr interface{ SQLState() string }
+
+	switch {
+	case xerrors.As(err, &pqErr):
+		return string(pqErr.Code)
+	case xerrors.As(err, &pgErr):
+		return pgErr.SQLState()
+	}
+
+	return ""
+}
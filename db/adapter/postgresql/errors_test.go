@@ -0,0 +1,75 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+	"golang.org/x/xerrors"
+
+	"go.zenithar.org/pkg/db"
+	"go.zenithar.org/pkg/errors"
+)
+
+func TestErrorCode(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want errors.ErrorCode
+	}{
+		{name: "nil", err: nil, want: errors.OK},
+		{name: "generic error", err: fmt.Errorf("foo"), want: errors.Unknown},
+		{name: "no rows", err: sql.ErrNoRows, want: errors.NotFound},
+		{name: "no result", err: db.ErrNoResult, want: errors.NotFound},
+		{name: "unique violation", err: &pq.Error{Code: "23505"}, want: errors.AlreadyExists},
+		{name: "foreign key violation", err: &pq.Error{Code: "23503"}, want: errors.FailedPrecondition},
+		{name: "serialization failure", err: sqlStateError("40001"), want: errors.Aborted},
+		{name: "statement timeout", err: &pq.Error{Code: "57014"}, want: errors.DeadlineExceeded},
+		{name: "connection failure", err: sqlStateError("08006"), want: errors.Unavailable},
+		{name: "bad connection", err: fmt.Errorf("postgresql: %w", driver.ErrBadConn), want: errors.Unavailable},
+		{name: "context deadline", err: context.DeadlineExceeded, want: errors.DeadlineExceeded},
+		{name: "syntax error", err: &pq.Error{Code: "42601"}, want: errors.Internal},
+	}
+
+	for _, tc := range testCases {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := ErrorCode(tt.err); got != tt.want {
+				t.Fatalf("got %v, wanted %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapError(t *testing.T) {
+	// Driver errors are kept
+	cause := &pq.Error{Code: "23505"}
+	err := wrapError(cause, "postgresql: unable to execute query")
+
+	var e *errors.Error
+	if !xerrors.As(err, &e) || e.Code != errors.AlreadyExists {
+		t.Fatalf("expected already exists error, got %v", err)
+	}
+	if !xerrors.Is(err, cause) {
+		t.Fatalf("driver error must be wrapped")
+	}
+
+	// No rows are translated
+	err = wrapError(fmt.Errorf("scan: %w", sql.ErrNoRows), "postgresql: unable to execute query")
+	if !xerrors.As(err, &e) || e.Code != errors.NotFound {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+	if !xerrors.Is(err, db.ErrNoResult) {
+		t.Fatalf("error must match db.ErrNoResult")
+	}
+
+	// Classified errors keep their code
+	if got := ErrorCode(wrapError(err, "postgresql: unable to retrieve query result")); got != errors.NotFound {
+		t.Fatalf("got %v, wanted %v", got, errors.NotFound)
+	}
+}
@@ -0,0 +1,72 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+
+	"go.zenithar.org/pkg/db"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+var _ db.Iterable = (*Default)(nil)
+
+// Iterate returns an iterator over rows matching the given filter, rows are
+// streamed from the database.
+func (d *Default) Iterate(ctx context.Context, filter interface{}, sortParams *db.SortParameters) (db.Iterator, error) {
+	// Check sort parameters
+	sorts, err := d.crud.Sorts(sortParams)
+	if err != nil {
+		return nil, err
+	}
+
+	where, err := d.crud.Where(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare query
+	q, args, err := sq.Select(d.crud.Columns()...).
+		From(d.table).
+		Where(where).
+		OrderBy(d.crud.OrderBy(sorts)...).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: unable to build query: %w", err)
+	}
+
+	// Do the query
+	rows, err := d.reader(ctx).QueryxContext(ctx, q, args...)
+	if err != nil {
+		return nil, wrapError(err, "postgresql: unable to execute query")
+	}
+
+	return &iterator{rows: rows}, nil
+}
+
+// -----------------------------------------------------------------------------
+
+type iterator struct {
+	rows *sqlx.Rows
+}
+
+func (it *iterator) Next() bool {
+	return it.rows.Next()
+}
+
+func (it *iterator) Scan(dest interface{}) error {
+	if err := it.rows.StructScan(dest); err != nil {
+		return wrapError(err, "postgresql: unable to scan row")
+	}
+	return nil
+}
+
+func (it *iterator) Err() error {
+	return it.rows.Err()
+}
+
+func (it *iterator) Close() error {
+	return it.rows.Close()
+}
@@ -0,0 +1,170 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+
+	"go.zenithar.org/pkg/db/migrations"
+	"go.zenithar.org/pkg/log"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// AutoMigrate applies pending migrations of the given set when enabled by the
+// configuration.
+func AutoMigrate(ctx context.Context, cfg *Configuration, session *sqlx.DB, set *migrations.Set) error {
+	if !cfg.AutoMigrate {
+		return nil
+	}
+
+	count, err := migrations.New(MigrationDriver(session, "schema_migrations"), set).Up(ctx, 0)
+	if err != nil {
+		return fmt.Errorf("postgresql: unable to apply migrations: %w", err)
+	}
+	if count > 0 {
+		log.For(ctx).Info("PostGreSQL schema migrated !")
+	}
+
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+type migrationDriver struct {
+	session *sqlx.DB
+	table   string
+	lockID  int64
+	conn    *sql.Conn
+}
+
+// MigrationDriver returns a migration driver persisting applied versions in
+// the given table, a session level advisory lock prevents concurrent runs.
+// Migration steps are executed in a transaction with a *sqlx.Tx session.
+func MigrationDriver(session *sqlx.DB, table string) migrations.Driver {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(table))
+
+	return &migrationDriver{
+		session: session,
+		table:   table,
+		lockID:  int64(h.Sum64()),
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func (d *migrationDriver) Lock(ctx context.Context) error {
+	// Advisory locks are bound to the connection
+	conn, err := d.session.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("postgresql: unable to acquire connection: %w", err)
+	}
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", d.lockID); err != nil {
+		log.SafeClose(conn, "Unable to close connection")
+		return fmt.Errorf("postgresql: unable to acquire advisory lock: %w", err)
+	}
+	d.conn = conn
+
+	// Initialize version table
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	version     BIGINT PRIMARY KEY,
+	description TEXT NOT NULL,
+	applied_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+)`, d.table)); err != nil {
+		// Release the lock, even if the context is canceled
+		log.CheckErrCtx(ctx, "Unable to release advisory lock", d.Unlock(context.Background()))
+		return fmt.Errorf("postgresql: unable to create version table: %w", err)
+	}
+
+	return nil
+}
+
+func (d *migrationDriver) Unlock(ctx context.Context) error {
+	if d.conn == nil {
+		return nil
+	}
+	defer func() {
+		log.SafeClose(d.conn, "Unable to close connection")
+		d.conn = nil
+	}()
+
+	if _, err := d.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", d.lockID); err != nil {
+		return fmt.Errorf("postgresql: unable to release advisory lock: %w", err)
+	}
+
+	return nil
+}
+
+func (d *migrationDriver) Applied(ctx context.Context) ([]migrations.Record, error) {
+	// Version table is created on first lock
+	var exists bool
+	if err := d.session.GetContext(ctx, &exists, "SELECT to_regclass($1) IS NOT NULL", d.table); err != nil {
+		return nil, fmt.Errorf("postgresql: unable to check version table: %w", err)
+	}
+	if !exists {
+		return []migrations.Record{}, nil
+	}
+
+	var records []struct {
+		Version     int64        `db:"version"`
+		Description string       `db:"description"`
+		AppliedAt   sql.NullTime `db:"applied_at"`
+	}
+
+	if err := d.session.SelectContext(ctx, &records, fmt.Sprintf("SELECT version, description, applied_at FROM %s ORDER BY version", d.table)); err != nil {
+		return nil, fmt.Errorf("postgresql: unable to retrieve applied versions: %w", err)
+	}
+
+	res := make([]migrations.Record, len(records))
+	for i, r := range records {
+		res[i] = migrations.Record{
+			Version:     uint64(r.Version),
+			Description: r.Description,
+			AppliedAt:   r.AppliedAt.Time,
+		}
+	}
+
+	return res, nil
+}
+
+func (d *migrationDriver) Apply(ctx context.Context, m *migrations.Migration, direction migrations.Direction) (err error) {
+	tx, err := d.session.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("postgresql: unable to start transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				err = fmt.Errorf("postgresql: unable to rollback transaction: %v: %w", errRollback, err)
+			}
+		}
+	}()
+
+	// Execute migration step and update version table
+	switch direction {
+	case migrations.Up:
+		if err = m.Up(ctx, tx); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, fmt.Sprintf("INSERT INTO %s (version, description) VALUES ($1, $2)", d.table), int64(m.Version), m.Description)
+	case migrations.Down:
+		if err = m.Down(ctx, tx); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE version = $1", d.table), int64(m.Version))
+	default:
+		return fmt.Errorf("postgresql: unsupported migration direction '%d'", direction)
+	}
+	if err != nil {
+		return fmt.Errorf("postgresql: unable to update version table: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("postgresql: unable to commit transaction: %w", err)
+	}
+
+	return nil
+}
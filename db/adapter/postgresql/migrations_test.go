@@ -0,0 +1,47 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"go.zenithar.org/pkg/db/migrations"
+)
+
+func TestMigrationDriver_Status(t *testing.T) {
+	session, j := fakeDB(t)
+	j.respond = func(query string) ([]string, [][]driver.Value) {
+		if strings.Contains(query, "to_regclass") {
+			return []string{"exists"}, [][]driver.Value{{false}}
+		}
+		return nil, nil
+	}
+
+	set := migrations.NewSet()
+	if err := set.Register(
+		&migrations.Migration{Version: 1, Description: "init", Up: migrations.SQL("SELECT 1")},
+		&migrations.Migration{Version: 2, Description: "users", Up: migrations.SQL("SELECT 2")},
+	); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+
+	// Fresh database, version table doesn't exist
+	status, err := migrations.New(MigrationDriver(session, "schema_migrations"), set).Status(context.Background())
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if len(status) != 2 {
+		t.Fatalf("got %d migrations, wanted 2", len(status))
+	}
+	for _, s := range status {
+		if s.Applied {
+			t.Errorf("migration %d must be pending", s.Version)
+		}
+	}
+	for _, q := range j.Events() {
+		if strings.Contains(q, "FROM schema_migrations") {
+			t.Errorf("version table must not be queried, got %q", q)
+		}
+	}
+}
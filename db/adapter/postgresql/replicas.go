@@ -0,0 +1,200 @@
+package postgresql
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"go.zenithar.org/pkg/log"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// Balancing is the enumeration for replica selection strategies
+type Balancing int
+
+const (
+	// RoundRobin selects healthy replicas in turn
+	RoundRobin Balancing = iota + 1
+	// LeastConnections selects the healthy replica with fewest in-use connections
+	LeastConnections
+)
+
+var balancings = [...]string{
+	"round-robin",
+	"least-connections",
+}
+
+func (b Balancing) String() string {
+	if b < RoundRobin || int(b) > len(balancings) {
+		return "unknown"
+	}
+	return balancings[b-1]
+}
+
+// -----------------------------------------------------------------------------
+
+type primaryKey struct{}
+
+// WithPrimary returns a context forcing reads to the primary, used to read
+// your own writes regardless of replication lag.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+func usePrimary(ctx context.Context) bool {
+	force, _ := ctx.Value(primaryKey{}).(bool)
+	return force
+}
+
+// -----------------------------------------------------------------------------
+
+// GroupOption defines replica group option builder.
+type GroupOption func(*Group)
+
+// WithBalancing sets the replica selection strategy, round-robin by default.
+func WithBalancing(b Balancing) GroupOption {
+	return func(g *Group) {
+		g.balancing = b
+	}
+}
+
+// WithHealthCheck sets the replica health check interval and timeout, 10s and
+// 2s by default.
+func WithHealthCheck(interval, timeout time.Duration) GroupOption {
+	return func(g *Group) {
+		g.interval = interval
+		g.timeout = timeout
+	}
+}
+
+type replica struct {
+	session *sqlx.DB
+	healthy int32
+}
+
+// Group is a primary connection with read replicas, reads are routed to
+// healthy replicas or fallback to the primary.
+type Group struct {
+	primary   *sqlx.DB
+	replicas  []*replica
+	balancing Balancing
+	interval  time.Duration
+	timeout   time.Duration
+	next      uint32
+}
+
+// NewGroup returns a replica group, all replicas are considered healthy until
+// the first health check.
+func NewGroup(primary *sqlx.DB, replicas []*sqlx.DB, opts ...GroupOption) *Group {
+	g := &Group{
+		primary:   primary,
+		replicas:  make([]*replica, len(replicas)),
+		balancing: RoundRobin,
+		interval:  10 * time.Second,
+		timeout:   2 * time.Second,
+	}
+	for i, r := range replicas {
+		g.replicas[i] = &replica{session: r, healthy: 1}
+	}
+
+	// Apply options
+	for _, o := range opts {
+		o(g)
+	}
+
+	return g
+}
+
+// -----------------------------------------------------------------------------
+
+// Primary returns the primary connection.
+func (g *Group) Primary() *sqlx.DB {
+	return g.primary
+}
+
+// Reader returns the connection to use for reads, the primary is returned when
+// forced by the context or when no replica is healthy.
+func (g *Group) Reader(ctx context.Context) *sqlx.DB {
+	if usePrimary(ctx) {
+		return g.primary
+	}
+
+	var selected *replica
+	switch g.balancing {
+	case LeastConnections:
+		for _, r := range g.replicas {
+			if !r.isHealthy() {
+				continue
+			}
+			if selected == nil || r.session.Stats().InUse < selected.session.Stats().InUse {
+				selected = r
+			}
+		}
+	default:
+		count := uint32(len(g.replicas))
+		for i := uint32(0); i < count && selected == nil; i++ {
+			if r := g.replicas[atomic.AddUint32(&g.next, 1)%count]; r.isHealthy() {
+				selected = r
+			}
+		}
+	}
+
+	if selected == nil {
+		return g.primary
+	}
+
+	return selected.session
+}
+
+// CheckHealth pings all replicas and updates their health status.
+func (g *Group) CheckHealth(ctx context.Context) {
+	for i, r := range g.replicas {
+		pingCtx, cancel := context.WithTimeout(ctx, g.timeout)
+		err := r.session.PingContext(pingCtx)
+		cancel()
+
+		healthy := int32(1)
+		if err != nil {
+			healthy = 0
+		}
+		if atomic.SwapInt32(&r.healthy, healthy) != healthy {
+			log.For(ctx).Warn("Replica health changed", zap.Int("replica", i), zap.Bool("healthy", err == nil), zap.Error(err))
+		}
+	}
+}
+
+// Run checks replica health periodically until the context is done.
+func (g *Group) Run(ctx context.Context) error {
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+
+	for {
+		g.CheckHealth(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// reader returns the transaction held by the context, or the connection
+// selected for reads.
+func (d *Default) reader(ctx context.Context) executor {
+	if h, ok := ctx.Value(txKey{}).(*txHolder); ok && h.session == d.session {
+		return h.tx
+	}
+	if d.replicas != nil {
+		return d.replicas.Reader(ctx)
+	}
+	return d.session
+}
+
+func (r *replica) isHealthy() bool {
+	return atomic.LoadInt32(&r.healthy) == 1
+}
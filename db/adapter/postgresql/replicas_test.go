@@ -0,0 +1,105 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func TestGroup_Reader(t *testing.T) {
+	primary, r1, r2 := &sqlx.DB{}, &sqlx.DB{}, &sqlx.DB{}
+	ctx := context.Background()
+
+	underTest := NewGroup(primary, []*sqlx.DB{r1, r2})
+
+	// Round robin
+	first, second := underTest.Reader(ctx), underTest.Reader(ctx)
+	if first == second || first == primary || second == primary {
+		t.Fatalf("replicas must be selected in turn")
+	}
+	if underTest.Reader(ctx) != first {
+		t.Fatalf("replicas must be selected in turn")
+	}
+
+	// Forced primary
+	if underTest.Reader(WithPrimary(ctx)) != primary {
+		t.Fatalf("primary must be selected")
+	}
+
+	// Unhealthy replicas are skipped
+	underTest.replicas[0].healthy = 0
+	for i := 0; i < 3; i++ {
+		if underTest.Reader(ctx) != r2 {
+			t.Fatalf("healthy replica must be selected")
+		}
+	}
+
+	// Fallback to primary
+	underTest.replicas[1].healthy = 0
+	if underTest.Reader(ctx) != primary {
+		t.Fatalf("primary must be selected")
+	}
+}
+
+func TestDefault_Reader(t *testing.T) {
+	session, replica := &sqlx.DB{}, &sqlx.DB{}
+	tx := &sqlx.Tx{}
+	ctx := context.Background()
+
+	// Without replicas
+	d := &Default{session: session}
+	if d.reader(ctx) != session {
+		t.Fatalf("session must be used")
+	}
+
+	// With replicas
+	d = &Default{session: session, replicas: NewGroup(session, []*sqlx.DB{replica})}
+	if d.reader(ctx) != replica {
+		t.Fatalf("replica must be used")
+	}
+	if d.reader(WithPrimary(ctx)) != session {
+		t.Fatalf("primary must be used")
+	}
+
+	// In transaction
+	txCtx := context.WithValue(ctx, txKey{}, &txHolder{session: session, tx: tx})
+	if d.reader(txCtx) != tx {
+		t.Fatalf("transaction must be used")
+	}
+}
+
+func TestBalancing_String(t *testing.T) {
+	testCases := []struct {
+		balancing Balancing
+		expected  string
+	}{
+		{RoundRobin, "round-robin"},
+		{LeastConnections, "least-connections"},
+		{Balancing(0), "unknown"},
+		{Balancing(42), "unknown"},
+	}
+	for _, tc := range testCases {
+		if got := tc.balancing.String(); got != tc.expected {
+			t.Errorf("got %q, wanted %q", got, tc.expected)
+		}
+	}
+}
+
+func TestWithReplicas(t *testing.T) {
+	session, other, replica := &sqlx.DB{}, &sqlx.DB{}, &sqlx.DB{}
+
+	// Matching primary
+	d := NewCRUDTable(session, "test", "users", nil, nil, WithReplicas(NewGroup(session, []*sqlx.DB{replica})))
+	if d.reader(context.Background()) != replica {
+		t.Fatalf("replica must be used")
+	}
+
+	// Mismatching primary
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("mismatching primary must panic")
+		}
+	}()
+	NewCRUDTable(session, "test", "users", nil, nil, WithReplicas(NewGroup(other, []*sqlx.DB{replica})))
+}
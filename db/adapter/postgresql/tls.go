@@ -0,0 +1,79 @@
+package postgresql
+
+import (
+	"crypto/tls"
+	"fmt"
+
+	"go.zenithar.org/pkg/errors"
+	"go.zenithar.org/pkg/tlsconfig"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// pqTLS sets lib/pq connection string TLS options, the driver loads files by
+// itself so that encrypted private keys are not supported.
+func pqTLS(cfg *Configuration, connStr *ConnectionURL) error {
+	if cfg.TLS.Passphrase != "" {
+		return errors.Newf(errors.InvalidArgument, nil, "postgresql: encrypted private keys are only supported by 'pgx' driver")
+	}
+
+	// Check settings
+	if _, err := tlsconfig.Client(cfg.TLS); err != nil {
+		return errors.Newf(errors.InvalidArgument, err, "postgresql: invalid TLS settings")
+	}
+
+	if connStr.Options == nil {
+		connStr.Options = map[string]string{}
+	}
+
+	connStr.Options["sslmode"] = "verify-full"
+	if cfg.TLS.InsecureSkipVerify {
+		connStr.Options["sslmode"] = "require"
+	}
+	if cfg.TLS.CAFile != "" {
+		connStr.Options["sslrootcert"] = cfg.TLS.CAFile
+	}
+	if cfg.TLS.CertFile != "" {
+		connStr.Options["sslcert"] = cfg.TLS.CertFile
+	}
+	if cfg.TLS.KeyFile != "" {
+		connStr.Options["sslkey"] = cfg.TLS.KeyFile
+	}
+
+	return nil
+}
+
+// pgxTLS returns a pgx connection configuration using the TLS settings for
+// all hosts, plain text fallbacks are removed.
+func pgxTLS(cfg *Configuration, dsn string) (*pgx.ConnConfig, error) {
+	tlsConfig, err := tlsconfig.Client(cfg.TLS)
+	if err != nil {
+		return nil, errors.Newf(errors.InvalidArgument, err, "postgresql: invalid TLS settings")
+	}
+
+	connConfig, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: unable to parse connection string: %w", err)
+	}
+
+	connConfig.TLSConfig = withServerName(tlsConfig, connConfig.Host)
+	fallbacks := connConfig.Fallbacks[:0]
+	for _, fb := range connConfig.Fallbacks {
+		if fb.TLSConfig == nil {
+			continue
+		}
+		fb.TLSConfig = withServerName(tlsConfig, fb.Host)
+		fallbacks = append(fallbacks, fb)
+	}
+	connConfig.Fallbacks = fallbacks
+
+	return connConfig, nil
+}
+
+// withServerName returns a copy of the TLS configuration verifying the given
+// host name.
+func withServerName(tlsConfig *tls.Config, host string) *tls.Config {
+	res := tlsConfig.Clone()
+	res.ServerName = host
+	return res
+}
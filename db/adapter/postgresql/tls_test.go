@@ -0,0 +1,106 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.zenithar.org/pkg/tlsconfig"
+)
+
+func TestPqTLS(t *testing.T) {
+	testCases := []struct {
+		name    string
+		options tlsconfig.Options
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:    "verify full",
+			options: tlsconfig.Options{},
+			want:    map[string]string{"sslmode": "verify-full"},
+		},
+		{
+			name:    "insecure",
+			options: tlsconfig.Options{InsecureSkipVerify: true},
+			want:    map[string]string{"sslmode": "require"},
+		},
+		{
+			name:    "encrypted private key",
+			options: tlsconfig.Options{Passphrase: "secret"},
+			wantErr: true,
+		},
+		{
+			name:    "missing ca file",
+			options: tlsconfig.Options{CAFile: "testdata/missing.pem"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			connStr := ConnectionURL{Host: "db.example.com"}
+			err := pqTLS(&Configuration{UseTLS: true, TLS: tt.options}, &connStr)
+			if tt.wantErr && err == nil {
+				t.Fatalf("expected error mst be raised")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("error must not be raised, got %v", err)
+			}
+			if err != nil {
+				return
+			}
+			if !cmp.Equal(connStr.Options, tt.want) {
+				t.Fatalf("got %v, wanted %v", connStr.Options, tt.want)
+			}
+		})
+	}
+}
+
+func TestPgxTLS(t *testing.T) {
+	testCases := []struct {
+		name          string
+		dsn           string
+		wantFallbacks int
+	}{
+		{
+			name: "disabled",
+			dsn:  "host=db.example.com sslmode=disable",
+		},
+		{
+			name: "prefer without plain text fallback",
+			dsn:  "host=db.example.com sslmode=prefer",
+		},
+		{
+			name:          "multiple hosts",
+			dsn:           "host=db1.example.com,db2.example.com sslmode=require",
+			wantFallbacks: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := pgxTLS(&Configuration{UseTLS: true}, tt.dsn)
+			if err != nil {
+				t.Fatalf("error must not be raised, got %v", err)
+			}
+			if got.TLSConfig == nil || got.TLSConfig.ServerName != got.Host {
+				t.Fatalf("TLS must verify %q, got %v", got.Host, got.TLSConfig)
+			}
+			if len(got.Fallbacks) != tt.wantFallbacks {
+				t.Fatalf("got %d fallbacks, wanted %d", len(got.Fallbacks), tt.wantFallbacks)
+			}
+			for _, fb := range got.Fallbacks {
+				if fb.TLSConfig == nil || fb.TLSConfig.ServerName != fb.Host {
+					t.Fatalf("TLS must verify %q, got %v", fb.Host, fb.TLSConfig)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.zenithar.org/pkg/db/adapter/internal/sqlcrud"
+	"go.zenithar.org/pkg/log"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+type txKey struct{}
+
+type txHolder struct {
+	session *sqlx.DB
+	tx      *sqlx.Tx
+	depth   int
+}
+
+// TxFunc is the transaction handler closure contract, the given context holds
+// the transaction.
+type TxFunc func(ctx context.Context) error
+
+// TxOption defines transaction option builder.
+type TxOption func(*txOptions)
+
+type txOptions struct {
+	isolation  sql.IsolationLevel
+	readOnly   bool
+	maxRetries int
+}
+
+// WithIsolation sets the transaction isolation level.
+func WithIsolation(level sql.IsolationLevel) TxOption {
+	return func(o *txOptions) {
+		o.isolation = level
+	}
+}
+
+// WithReadOnly starts a read-only transaction.
+func WithReadOnly() TxOption {
+	return func(o *txOptions) {
+		o.readOnly = true
+	}
+}
+
+// WithMaxRetries sets the retry count on serialization failures and deadlocks,
+// 3 by default.
+func WithMaxRetries(count int) TxOption {
+	return func(o *txOptions) {
+		o.maxRetries = count
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// WithTx runs the given function in a transaction stored in the context, all
+// Default methods called with this context use the transaction. Nested calls
+// use savepoints, options are only applied to the outermost transaction. The
+// whole transaction is retried on serialization failures and deadlocks.
+func WithTx(ctx context.Context, session *sqlx.DB, fn TxFunc, opts ...TxOption) error {
+	// Nested transaction
+	if h, ok := ctx.Value(txKey{}).(*txHolder); ok && h.session == session {
+		return savepoint(ctx, h, fn)
+	}
+
+	// Apply options
+	o := &txOptions{
+		isolation:  sql.LevelDefault,
+		maxRetries: 3,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := transaction(ctx, session, o, fn)
+		if err == nil || !IsRetryable(err) || attempt >= o.maxRetries {
+			return err
+		}
+
+		log.For(ctx).Debug("Retrying transaction", zap.Int("attempt", attempt+1), zap.Error(err))
+
+		// Wait before retrying
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt+1) * 10 * time.Millisecond):
+		}
+	}
+}
+
+// WithTx runs the given function in a transaction using the table session.
+func (d *Default) WithTx(ctx context.Context, fn TxFunc, opts ...TxOption) error {
+	return WithTx(ctx, d.session, fn, opts...)
+}
+
+// TxFromContext returns the transaction held by the context, nil if none.
+func TxFromContext(ctx context.Context) *sqlx.Tx {
+	if h, ok := ctx.Value(txKey{}).(*txHolder); ok {
+		return h.tx
+	}
+	return nil
+}
+
+// IsRetryable returns true for serialization failures and deadlocks.
+func IsRetryable(err error) bool {
+	code := sqlState(err)
+	return code == "40001" || code == "40P01"
+}
+
+// -----------------------------------------------------------------------------
+
+type executor = sqlcrud.Executor
+
+// executor returns the transaction held by the context, or the session.
+func (d *Default) executor(ctx context.Context) executor {
+	if h, ok := ctx.Value(txKey{}).(*txHolder); ok && h.session == d.session {
+		return h.tx
+	}
+	return d.session
+}
+
+func transaction(ctx context.Context, session *sqlx.DB, o *txOptions, fn TxFunc) (err error) {
+	tx, err := session.BeginTxx(ctx, &sql.TxOptions{
+		Isolation: o.isolation,
+		ReadOnly:  o.readOnly,
+	})
+	if err != nil {
+		return wrapError(err, "postgresql: unable to start transaction")
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			// Rollback before propagating the panic
+			_ = tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	// Run the function
+	if err = fn(context.WithValue(ctx, txKey{}, &txHolder{session: session, tx: tx})); err != nil {
+		if errRollback := tx.Rollback(); errRollback != nil {
+			log.For(ctx).Error("Unable to rollback transaction", zap.Error(errRollback))
+		}
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return wrapError(err, "postgresql: unable to commit transaction")
+	}
+
+	return nil
+}
+
+func savepoint(ctx context.Context, h *txHolder, fn TxFunc) error {
+	name := fmt.Sprintf("sp_%d", h.depth+1)
+
+	if _, err := h.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return wrapError(err, "postgresql: unable to create savepoint")
+	}
+
+	// Run the function
+	if err := fn(context.WithValue(ctx, txKey{}, &txHolder{session: h.session, tx: h.tx, depth: h.depth + 1})); err != nil {
+		if _, errRollback := h.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); errRollback != nil {
+			log.For(ctx).Error("Unable to rollback savepoint", zap.Error(errRollback))
+		}
+		return err
+	}
+
+	if _, err := h.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return wrapError(err, "postgresql: unable to release savepoint")
+	}
+
+	return nil
+}
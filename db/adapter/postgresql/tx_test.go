@@ -0,0 +1,245 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "sqlstate " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+func TestIsRetryable(t *testing.T) {
+
+	testCases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: false,
+		},
+		{
+			name: "generic error",
+			err:  fmt.Errorf("foo"),
+			want: false,
+		},
+		{
+			name: "pq serialization failure",
+			err:  &pq.Error{Code: "40001"},
+			want: true,
+		},
+		{
+			name: "wrapped pq deadlock",
+			err:  fmt.Errorf("postgresql: unable to execute query: %w", &pq.Error{Code: "40P01"}),
+			want: true,
+		},
+		{
+			name: "pq unique violation",
+			err:  &pq.Error{Code: "23505"},
+			want: false,
+		},
+		{
+			name: "pgx serialization failure",
+			err:  fmt.Errorf("postgresql: unable to execute query: %w", sqlStateError("40001")),
+			want: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Fatalf("got %v, wanted %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecutor(t *testing.T) {
+	session := &sqlx.DB{}
+	tx := &sqlx.Tx{}
+	ctx := context.WithValue(context.Background(), txKey{}, &txHolder{session: session, tx: tx})
+
+	if TxFromContext(context.Background()) != nil {
+		t.Fatalf("context must not hold a transaction")
+	}
+	if TxFromContext(ctx) != tx {
+		t.Fatalf("context must hold the transaction")
+	}
+
+	// Same session
+	if exec := NewCRUDTable(session, "db", "users", []string{"id"}, nil).executor(ctx); exec != tx {
+		t.Fatalf("got %v, wanted transaction", exec)
+	}
+
+	// Other session
+	other := &sqlx.DB{}
+	if exec := NewCRUDTable(other, "db", "users", []string{"id"}, nil).executor(ctx); exec != other {
+		t.Fatalf("got %v, wanted session", exec)
+	}
+}
+
+func TestWithTx(t *testing.T) {
+	errFailed := fmt.Errorf("failed")
+
+	testCases := []struct {
+		name      string
+		opts      []TxOption
+		failures  []error
+		wantErr   bool
+		wantCalls int
+		want      []string
+	}{
+		{
+			name:      "commit",
+			wantCalls: 1,
+			want:      []string{"BEGIN", "INSERT", "COMMIT"},
+		},
+		{
+			name:      "rollback on error",
+			failures:  []error{errFailed},
+			wantErr:   true,
+			wantCalls: 1,
+			want:      []string{"BEGIN", "INSERT", "ROLLBACK"},
+		},
+		{
+			name:      "retry on serialization failure",
+			failures:  []error{&pq.Error{Code: "40001"}},
+			wantCalls: 2,
+			want:      []string{"BEGIN", "INSERT", "ROLLBACK", "BEGIN", "INSERT", "COMMIT"},
+		},
+		{
+			name:      "retry on deadlock",
+			failures:  []error{fmt.Errorf("postgresql: unable to execute query: %w", &pq.Error{Code: "40P01"})},
+			wantCalls: 2,
+			want:      []string{"BEGIN", "INSERT", "ROLLBACK", "BEGIN", "INSERT", "COMMIT"},
+		},
+		{
+			name:      "retry limit",
+			opts:      []TxOption{WithMaxRetries(1)},
+			failures:  []error{&pq.Error{Code: "40001"}, &pq.Error{Code: "40001"}, nil},
+			wantErr:   true,
+			wantCalls: 2,
+			want:      []string{"BEGIN", "INSERT", "ROLLBACK", "BEGIN", "INSERT", "ROLLBACK"},
+		},
+		{
+			name:      "no retry on other errors",
+			failures:  []error{&pq.Error{Code: "23505"}, nil},
+			wantErr:   true,
+			wantCalls: 1,
+			want:      []string{"BEGIN", "INSERT", "ROLLBACK"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			session, j := fakeDB(t)
+
+			calls := 0
+			err := WithTx(context.Background(), session, func(ctx context.Context) error {
+				calls++
+				if _, err := TxFromContext(ctx).ExecContext(ctx, "INSERT"); err != nil {
+					return err
+				}
+				if calls <= len(tt.failures) {
+					return tt.failures[calls-1]
+				}
+				return nil
+			}, tt.opts...)
+			if tt.wantErr && err == nil {
+				t.Fatalf("expected error mst be raised")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("error must not be raised, got %v", err)
+			}
+			if calls != tt.wantCalls {
+				t.Fatalf("got %d calls, wanted %d", calls, tt.wantCalls)
+			}
+			if diff := cmp.Diff(tt.want, j.Events()); diff != "" {
+				t.Fatalf("%s", diff)
+			}
+		})
+	}
+}
+
+func TestWithTx_Panic(t *testing.T) {
+	session, j := fakeDB(t)
+
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Fatalf("panic must be propagated, got %v", r)
+			}
+		}()
+
+		_ = WithTx(context.Background(), session, func(ctx context.Context) error {
+			panic("boom")
+		})
+	}()
+
+	if diff := cmp.Diff([]string{"BEGIN", "ROLLBACK"}, j.Events()); diff != "" {
+		t.Fatalf("%s", diff)
+	}
+}
+
+func TestWithTx_Nested(t *testing.T) {
+	session, j := fakeDB(t)
+
+	err := WithTx(context.Background(), session, func(ctx context.Context) error {
+		outer := TxFromContext(ctx)
+
+		// Released savepoint
+		if err := WithTx(ctx, session, func(ctx context.Context) error {
+			if TxFromContext(ctx) != outer {
+				t.Fatalf("nested call must use the outer transaction")
+			}
+
+			// Deeper savepoint
+			return WithTx(ctx, session, func(ctx context.Context) error {
+				return nil
+			})
+		}); err != nil {
+			return err
+		}
+
+		// Rolled back savepoint, the outer transaction continues
+		if err := WithTx(ctx, session, func(ctx context.Context) error {
+			return fmt.Errorf("failed")
+		}); err == nil {
+			t.Fatalf("expected error mst be raised")
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+
+	want := []string{
+		"BEGIN",
+		"SAVEPOINT sp_1",
+		"SAVEPOINT sp_2",
+		"RELEASE SAVEPOINT sp_2",
+		"RELEASE SAVEPOINT sp_1",
+		"SAVEPOINT sp_1",
+		"ROLLBACK TO SAVEPOINT sp_1",
+		"COMMIT",
+	}
+	if diff := cmp.Diff(want, j.Events()); diff != "" {
+		t.Fatalf("%s", diff)
+	}
+}
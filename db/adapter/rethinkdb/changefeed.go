@@ -0,0 +1,71 @@
+package rethinkdb
+
+import (
+	"context"
+
+	"go.zenithar.org/pkg/db"
+	"go.zenithar.org/pkg/errors"
+	"go.zenithar.org/pkg/log"
+
+	r "gopkg.in/rethinkdb/rethinkdb-go.v6"
+)
+
+var _ db.ChangeFeed = (*Default)(nil)
+
+type changeEvent struct {
+	Type   string                 `rethinkdb:"type"`
+	OldVal map[string]interface{} `rethinkdb:"old_val"`
+	NewVal map[string]interface{} `rethinkdb:"new_val"`
+}
+
+// Watch emits table changes using changefeeds, resume tokens are not
+// supported so that the feed always starts from now.
+func (d *Default) Watch(ctx context.Context, resumeToken string, fn db.ChangeHandler) error {
+	if resumeToken != "" {
+		return errors.Newf(errors.Unimplemented, nil, "rethinkdb: changefeeds can't be resumed")
+	}
+
+	cursor, err := r.Table(d.table).Changes(r.ChangesOpts{
+		IncludeTypes: true,
+	}).Run(d.session, r.RunOpts{
+		Context: ctx,
+	})
+	if err != nil {
+		return wrapError(err, "rethinkdb: unable to open changefeed")
+	}
+	defer log.SafeClose(cursor, "Unable to close changefeed cursor")
+
+	for {
+		var evt changeEvent
+		if !cursor.Next(&evt) {
+			break
+		}
+
+		change := &db.Change{
+			Table: d.table,
+			Old:   evt.OldVal,
+			New:   evt.NewVal,
+		}
+		switch evt.Type {
+		case "add":
+			change.Type = db.ChangeInsert
+		case "change":
+			change.Type = db.ChangeUpdate
+		case "remove":
+			change.Type = db.ChangeDelete
+		default:
+			// Ignore initial values and feed states
+			continue
+		}
+
+		if err := fn(ctx, change); err != nil {
+			return err
+		}
+	}
+
+	if err := cursor.Err(); err != nil && ctx.Err() == nil {
+		return wrapError(err, "rethinkdb: changefeed failed")
+	}
+
+	return ctx.Err()
+}
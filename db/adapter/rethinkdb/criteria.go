@@ -0,0 +1,69 @@
+package rethinkdb
+
+import (
+	"fmt"
+
+	"go.zenithar.org/pkg/db"
+
+	r "gopkg.in/rethinkdb/rethinkdb-go.v6"
+)
+
+// ConvertCriteria converts the given criterion to a rethinkdb filter term,
+// fields must be part of filterable fields, all fields are allowed if empty.
+func ConvertCriteria(c *db.Criterion, filterableFields map[string]bool) (r.Term, error) {
+	// Check criteria first
+	if err := c.Validate(func(field string) bool {
+		return len(filterableFields) == 0 || filterableFields[field]
+	}); err != nil {
+		return r.Term{}, err
+	}
+
+	return convertCriterion(c)
+}
+
+// -----------------------------------------------------------------------------
+
+func convertCriterion(c *db.Criterion) (r.Term, error) {
+	field := r.Row.Field(c.Field)
+
+	switch c.Operator {
+	case db.OpEq:
+		return field.Eq(c.Values[0]), nil
+	case db.OpNeq:
+		return field.Ne(c.Values[0]), nil
+	case db.OpIn:
+		return r.Expr(c.Values).Contains(field), nil
+	case db.OpGt:
+		return field.Gt(c.Values[0]), nil
+	case db.OpGte:
+		return field.Ge(c.Values[0]), nil
+	case db.OpLt:
+		return field.Lt(c.Values[0]), nil
+	case db.OpLte:
+		return field.Le(c.Values[0]), nil
+	case db.OpLike:
+		return field.Match(db.LikeToRegexp(c.Values[0].(string))).Ne(nil), nil
+	case db.OpIsNull:
+		return r.Row.HasFields(c.Field).Not().Or(field.Eq(nil)), nil
+	case db.OpAnd, db.OpOr, db.OpNot:
+		children := make([]interface{}, 0, len(c.Children))
+		for _, child := range c.Children {
+			sub, err := convertCriterion(child)
+			if err != nil {
+				return r.Term{}, err
+			}
+			children = append(children, sub)
+		}
+		switch c.Operator {
+		case db.OpAnd:
+			return r.And(children...), nil
+		case db.OpOr:
+			return r.Or(children...), nil
+		default:
+			return children[0].(r.Term).Not(), nil
+		}
+	default:
+	}
+
+	return r.Term{}, fmt.Errorf("rethinkdb: unsupported criteria operator '%d'", c.Operator)
+}
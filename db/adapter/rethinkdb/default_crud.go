@@ -3,10 +3,17 @@ package rethinkdb
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"strings"
+	"time"
 
 	"go.zenithar.org/pkg/db"
+	"go.zenithar.org/pkg/errors"
+
+	"golang.org/x/xerrors"
 
 	r "gopkg.in/rethinkdb/rethinkdb-go.v6"
+	"gopkg.in/rethinkdb/rethinkdb-go.v6/encoding"
 )
 
 // Default contains the basic implementation of the EntityCRUD interface
@@ -14,15 +21,31 @@ type Default struct {
 	table   string
 	db      string
 	session *r.Session
+
+	filterableFields map[string]bool
+	sortableFields   map[string]bool
+	primaryKey       string
+	versionField     string
+	tracking         db.Tracking
 }
 
 // NewCRUDTable sets up a new Default struct
-func NewCRUDTable(session *r.Session, db, table string) *Default {
-	return &Default{
-		db:      db,
-		table:   table,
-		session: session,
+func NewCRUDTable(session *r.Session, db, table string, opts ...Option) *Default {
+	d := &Default{
+		db:               db,
+		table:            table,
+		session:          session,
+		filterableFields: map[string]bool{},
+		sortableFields:   map[string]bool{},
+		primaryKey:       "id",
 	}
+
+	// Apply options
+	for _, o := range opts {
+		o(d)
+	}
+
+	return d
 }
 
 // -----------------------------------------------------------------------------
@@ -49,16 +72,27 @@ func (d *Default) GetSession() interface{} {
 
 // -----------------------------------------------------------------------------
 
+// Create inserts a document into the database
+func (d *Default) Create(ctx context.Context, data interface{}) error {
+	return d.Insert(ctx, data)
+}
+
 // Insert inserts a document into the database
 func (d *Default) Insert(ctx context.Context, data interface{}) error {
-	_, err := r.Table(d.table).Insert(data).RunWrite(d.session, r.RunOpts{
+	// Apply tracking fields
+	doc, err := d.track(data, d.tracking.OnCreate(ctx, time.Now().UTC()))
+	if err != nil {
+		return err
+	}
+
+	_, err = r.Table(d.table).Insert(doc).RunWrite(d.session, r.RunOpts{
 		Context: ctx,
 	})
 	if err != nil {
 		if err == r.ErrEmptyResult {
 			return db.ErrNoResult
 		}
-		return fmt.Errorf("rethinkdb: unable to execute query: %w", err)
+		return wrapError(err, "rethinkdb: unable to execute query")
 	}
 
 	return nil
@@ -73,7 +107,7 @@ func (d *Default) InsertOrUpdate(ctx context.Context, id interface{}, data inter
 		if err == r.ErrEmptyResult {
 			return db.ErrNoResult
 		}
-		return fmt.Errorf("rethinkdb: unable to execute query: %w", err)
+		return wrapError(err, "rethinkdb: unable to execute query")
 	}
 
 	return nil
@@ -85,14 +119,14 @@ func (d *Default) Find(ctx context.Context, id interface{}, value interface{}) e
 		Context: ctx,
 	})
 	if err != nil {
-		return fmt.Errorf("rethinkdb: unable to execute query: %w", err)
+		return wrapError(err, "rethinkdb: unable to execute query")
 	}
 
 	if err := cursor.One(value); err != nil {
 		if err == r.ErrEmptyResult {
 			return db.ErrNoResult
 		}
-		return fmt.Errorf("rethinkdb: unable to retrieve query result: %w", err)
+		return wrapError(err, "rethinkdb: unable to retrieve query result")
 	}
 
 	return nil
@@ -104,14 +138,14 @@ func (d *Default) FindOneBy(ctx context.Context, key string, value interface{},
 		Context: ctx,
 	})
 	if err != nil {
-		return fmt.Errorf("rethinkdb: unable to execute query: %w", err)
+		return wrapError(err, "rethinkdb: unable to execute query")
 	}
 
 	if err := cursor.One(result); err != nil {
 		if err == r.ErrEmptyResult {
 			return db.ErrNoResult
 		}
-		return fmt.Errorf("rethinkdb: unable to retrieve query result: %w", err)
+		return wrapError(err, "rethinkdb: unable to retrieve query result")
 	}
 
 	return nil
@@ -125,14 +159,14 @@ func (d *Default) FindBy(ctx context.Context, key string, value interface{}, res
 		Context: ctx,
 	})
 	if err != nil {
-		return fmt.Errorf("rethinkdb: unable to execute query: %w", err)
+		return wrapError(err, "rethinkdb: unable to execute query")
 	}
 
 	if err := cursor.All(results); err != nil {
 		if err == r.ErrEmptyResult {
 			return db.ErrNoResult
 		}
-		return fmt.Errorf("rethinkdb: unable to retrieve query result: %w", err)
+		return wrapError(err, "rethinkdb: unable to retrieve query result")
 	}
 
 	return nil
@@ -146,7 +180,7 @@ func (d *Default) FindByAndCount(ctx context.Context, key string, value interfac
 		Context: ctx,
 	})
 	if err != nil {
-		return 0, fmt.Errorf("rethinkdb: unable to execute query: %w", err)
+		return 0, wrapError(err, "rethinkdb: unable to execute query")
 	}
 
 	var count int
@@ -154,7 +188,7 @@ func (d *Default) FindByAndCount(ctx context.Context, key string, value interfac
 		if err == r.ErrEmptyResult {
 			return 0, db.ErrNoResult
 		}
-		return 0, fmt.Errorf("rethinkdb: unable to retrieve query result: %w", err)
+		return 0, wrapError(err, "rethinkdb: unable to retrieve query result")
 	}
 
 	return count, nil
@@ -162,18 +196,23 @@ func (d *Default) FindByAndCount(ctx context.Context, key string, value interfac
 
 // Where is used to fetch documents that match th filter from the database
 func (d *Default) Where(ctx context.Context, filter interface{}, results interface{}) error {
-	cursor, err := r.Table(d.table).Filter(filter).Run(d.session, r.RunOpts{
+	term, err := d.where(filter)
+	if err != nil {
+		return err
+	}
+
+	cursor, err := term.Run(d.session, r.RunOpts{
 		Context: ctx,
 	})
 	if err != nil {
-		return fmt.Errorf("rethinkdb: unable to execute query: %w", err)
+		return wrapError(err, "rethinkdb: unable to execute query")
 	}
 
 	if err := cursor.All(results); err != nil {
 		if err == r.ErrEmptyResult {
 			return db.ErrNoResult
 		}
-		return fmt.Errorf("rethinkdb: unable to retrieve query result: %w", err)
+		return wrapError(err, "rethinkdb: unable to retrieve query result")
 	}
 
 	return nil
@@ -181,11 +220,16 @@ func (d *Default) Where(ctx context.Context, filter interface{}, results interfa
 
 // WhereCount returns the document count that match the filter
 func (d *Default) WhereCount(ctx context.Context, filter interface{}) (int, error) {
-	cursor, err := r.Table(d.table).Filter(filter).Count().Run(d.session, r.RunOpts{
+	term, err := d.where(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	cursor, err := term.Count().Run(d.session, r.RunOpts{
 		Context: ctx,
 	})
 	if err != nil {
-		return 0, fmt.Errorf("rethinkdb: unable to execute query: %w", err)
+		return 0, wrapError(err, "rethinkdb: unable to execute query")
 	}
 
 	var count int
@@ -193,7 +237,7 @@ func (d *Default) WhereCount(ctx context.Context, filter interface{}) (int, erro
 		if err == r.ErrEmptyResult {
 			return 0, db.ErrNoResult
 		}
-		return 0, fmt.Errorf("rethinkdb: unable to retrieve query result: %w", err)
+		return 0, wrapError(err, "rethinkdb: unable to retrieve query result")
 	}
 
 	return count, nil
@@ -201,18 +245,23 @@ func (d *Default) WhereCount(ctx context.Context, filter interface{}) (int, erro
 
 // WhereAndFetchOne returns one document that match the filter
 func (d *Default) WhereAndFetchOne(ctx context.Context, filter interface{}, result interface{}) error {
-	cursor, err := r.Table(d.table).Filter(filter).Run(d.session, r.RunOpts{
+	term, err := d.where(filter)
+	if err != nil {
+		return err
+	}
+
+	cursor, err := term.Run(d.session, r.RunOpts{
 		Context: ctx,
 	})
 	if err != nil {
-		return fmt.Errorf("rethinkdb: unable to execute query: %w", err)
+		return wrapError(err, "rethinkdb: unable to execute query")
 	}
 
 	if err := cursor.One(result); err != nil {
 		if err == r.ErrEmptyResult {
 			return db.ErrNoResult
 		}
-		return fmt.Errorf("rethinkdb: unable to retrieve query result: %w", err)
+		return wrapError(err, "rethinkdb: unable to retrieve query result")
 	}
 
 	return nil
@@ -220,18 +269,23 @@ func (d *Default) WhereAndFetchOne(ctx context.Context, filter interface{}, resu
 
 // WhereAndFetchLimit returns paginated list of document
 func (d *Default) WhereAndFetchLimit(ctx context.Context, filter interface{}, paginator *db.Pagination, results interface{}) error {
-	cursor, err := r.Table(d.table).Filter(filter).Run(d.session, r.RunOpts{
+	term, err := d.where(filter)
+	if err != nil {
+		return err
+	}
+
+	cursor, err := term.Run(d.session, r.RunOpts{
 		Context: ctx,
 	})
 	if err != nil {
-		return fmt.Errorf("rethinkdb: unable to execute query: %w", err)
+		return wrapError(err, "rethinkdb: unable to execute query")
 	}
 
 	if err := cursor.All(results); err != nil {
 		if err == r.ErrEmptyResult {
 			return db.ErrNoResult
 		}
-		return fmt.Errorf("rethinkdb: unable to retrieve query result: %w", err)
+		return wrapError(err, "rethinkdb: unable to retrieve query result")
 	}
 
 	return nil
@@ -246,7 +300,50 @@ func (d *Default) Update(ctx context.Context, selector interface{}, data interfa
 		if err == r.ErrEmptyResult {
 			return db.ErrNoResult
 		}
-		return fmt.Errorf("rethinkdb: unable to execute query: %w", err)
+		return wrapError(err, "rethinkdb: unable to execute query")
+	}
+
+	return nil
+}
+
+// UpdateWhere sets the given fields of all documents matching the filter,
+// returns db.ErrNoModification if none matches.
+func (d *Default) UpdateWhere(ctx context.Context, updates map[string]interface{}, filter interface{}) error {
+	term, err := d.where(filter)
+	if err != nil {
+		return err
+	}
+
+	// Check and increment version
+	var expected interface{}
+	if d.versionField != "" {
+		expected, updates, err = db.ExtractVersion(updates, d.versionField)
+		if err != nil {
+			return err
+		}
+		updates[d.versionField] = r.Row.Field(d.versionField).Add(1)
+		term = term.Filter(r.Row.Field(d.versionField).Eq(expected))
+	}
+
+	// Apply tracking fields
+	values := make(map[string]interface{}, len(updates))
+	for k, v := range updates {
+		values[k] = v
+	}
+	for k, v := range d.tracking.OnUpdate(ctx, time.Now().UTC()) {
+		values[k] = v
+	}
+
+	res, err := term.Update(values).RunWrite(d.session, r.RunOpts{
+		Context: ctx,
+	})
+	if err != nil {
+		return wrapError(err, "rethinkdb: unable to execute query")
+	}
+
+	// If no document matched return an handled error
+	if res.Replaced+res.Unchanged == 0 {
+		return d.noModification(ctx, filter, expected)
 	}
 
 	return nil
@@ -261,7 +358,7 @@ func (d *Default) UpdateID(ctx context.Context, id interface{}, data interface{}
 		if err == r.ErrEmptyResult {
 			return db.ErrNoResult
 		}
-		return fmt.Errorf("rethinkdb: unable to execute query: %w", err)
+		return wrapError(err, "rethinkdb: unable to execute query")
 	}
 
 	return nil
@@ -276,7 +373,35 @@ func (d *Default) DeleteAll(ctx context.Context, pred interface{}) error {
 		if err == r.ErrEmptyResult {
 			return db.ErrNoResult
 		}
-		return fmt.Errorf("rethinkdb: unable to execute query: %w", err)
+		return wrapError(err, "rethinkdb: unable to execute query")
+	}
+
+	return nil
+}
+
+// RemoveOne deletes one document that match the filter
+func (d *Default) RemoveOne(ctx context.Context, filter interface{}) error {
+	term, err := d.where(filter)
+	if err != nil {
+		return err
+	}
+
+	// Mark as deleted
+	write := term.Limit(1).Delete()
+	if d.tracking.SoftDelete() {
+		write = term.Limit(1).Update(d.tracking.OnDelete(ctx, time.Now().UTC()))
+	}
+
+	res, err := write.RunWrite(d.session, r.RunOpts{
+		Context: ctx,
+	})
+	if err != nil {
+		return wrapError(err, "rethinkdb: unable to execute query")
+	}
+
+	// If no document matched return an handled error
+	if res.Deleted+res.Replaced == 0 {
+		return db.ErrNoModification
 	}
 
 	return nil
@@ -291,7 +416,7 @@ func (d *Default) Delete(ctx context.Context, id interface{}) error {
 		if err == r.ErrEmptyResult {
 			return db.ErrNoResult
 		}
-		return fmt.Errorf("rethinkdb: unable to execute query: %w", err)
+		return wrapError(err, "rethinkdb: unable to execute query")
 	}
 
 	return nil
@@ -302,28 +427,62 @@ func (d *Default) List(ctx context.Context, results interface{}, sortParams *db.
 	return d.Search(ctx, results, nil, sortParams, pagination)
 }
 
-// Search all entities in the database
+// Search all entities in the database, results are emptied if none matches.
 func (d *Default) Search(ctx context.Context, results interface{}, filter interface{}, sortParams *db.SortParameters, pagination *db.Pagination) error {
-	term := r.Table(d.table)
+	_, err := d.search(ctx, filter, pagination, sortParams, results)
+	if !xerrors.Is(err, db.ErrNoResult) {
+		return err
+	}
+
+	// No matching document is not an error
+	if pagination != nil {
+		pagination.SetTotal(0)
+	}
+	if items := reflect.Indirect(reflect.ValueOf(results)); items.Kind() == reflect.Slice && items.CanSet() {
+		items.Set(reflect.MakeSlice(items.Type(), 0, 0))
+	}
+
+	return nil
+}
+
+// -----------------------------------------------------------------------------
 
-	// Filter
-	if filter != nil {
-		term = term.Filter(filter)
+// search returns matching documents using offset or keyset pagination, and
+// the total count of matching documents.
+func (d *Default) search(ctx context.Context, filter interface{}, pagination *db.Pagination, sortParams *db.SortParameters, results interface{}) (int, error) {
+	term, err := d.where(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	// Check sort parameters
+	sorts, err := d.sorts(sortParams)
+	if err != nil {
+		return 0, err
+	}
+
+	// Keyset pagination, total count is not computed
+	if pagination != nil && pagination.IsKeyset() {
+		return d.searchAfter(ctx, term, filter, pagination, sorts, results)
 	}
 
 	// Get total
+	count, err := d.WhereCount(ctx, filter)
+	if err != nil {
+		return 0, wrapError(err, "rethinkdb: unable to retrieve collection count")
+	}
+
+	// If no result skip data request
+	if count == 0 {
+		return 0, db.ErrNoResult
+	}
+
 	if pagination != nil {
-		total, err := d.WhereCount(ctx, filter)
-		if err != nil {
-			return fmt.Errorf("rethinkdb: unable to execute query: %w", err)
-		}
-		pagination.SetTotal(uint(total))
+		pagination.SetTotal(uint(count))
 	}
 
 	// Sort
-	if sortParams != nil {
-		term = term.OrderBy(ConvertSortParameters(*sortParams)...)
-	}
+	term = term.OrderBy(ConvertSortParameters(sorts)...)
 
 	// Slice result
 	if pagination != nil {
@@ -335,17 +494,189 @@ func (d *Default) Search(ctx context.Context, results interface{}, filter interf
 		Context: ctx,
 	})
 	if err != nil {
-		return fmt.Errorf("rethinkdb: unable to execute query: %w", err)
+		return 0, wrapError(err, "rethinkdb: unable to execute query")
 	}
 
 	// Fetch cursor
 	err = cursor.All(results)
 	if err != nil {
 		if err == r.ErrEmptyResult {
-			return db.ErrNoResult
+			return 0, db.ErrNoResult
 		}
-		return fmt.Errorf("rethinkdb: unable to retrieve query result: %w", err)
+		return 0, wrapError(err, "rethinkdb: unable to retrieve query result")
 	}
 
-	return nil
+	return count, nil
+}
+
+// searchAfter executes the query using keyset pagination, one more document is
+// fetched to detect the next page. It returns the page document count.
+func (d *Default) searchAfter(ctx context.Context, term r.Term, filter interface{}, pagination *db.Pagination, sorts db.SortParameters, results interface{}) (int, error) {
+	// Use lower-cased field names as sort conversion does
+	fields := make([]db.SortField, len(sorts))
+	for i, f := range sorts {
+		fields[i] = db.SortField{Field: strings.ToLower(f.Field), Direction: f.Direction}
+	}
+
+	// Check cursor origin
+	if err := pagination.Bind(d.table, filter, fields); err != nil {
+		return 0, err
+	}
+
+	// Start after cursor position
+	if after := pagination.After(); len(after) > 0 {
+		c, err := db.KeysetCriterion(fields, after)
+		if err != nil {
+			return 0, err
+		}
+		keyset, err := convertCriterion(c)
+		if err != nil {
+			return 0, err
+		}
+		term = term.Filter(keyset)
+	}
+
+	// Apply sorts
+	term = term.OrderBy(ConvertSortParameters(fields)...).Limit(pagination.PerPage + 1)
+
+	// Run the query
+	cursor, err := term.Run(d.session, r.RunOpts{
+		Context: ctx,
+	})
+	if err != nil {
+		return 0, wrapError(err, "rethinkdb: unable to execute query")
+	}
+
+	// Fetch cursor
+	if err := cursor.All(results); err != nil && err != r.ErrEmptyResult {
+		return 0, wrapError(err, "rethinkdb: unable to retrieve query result")
+	}
+
+	// Truncate extra element
+	items := reflect.Indirect(reflect.ValueOf(results))
+	hasNext := items.Len() > int(pagination.PerPage)
+	if hasNext {
+		items.Set(items.Slice(0, int(pagination.PerPage)))
+	}
+	if items.Len() == 0 {
+		if len(pagination.After()) == 0 {
+			return 0, db.ErrNoResult
+		}
+		return 0, pagination.SetNext(nil, false)
+	}
+
+	// Extract last element sort key values
+	encoded, err := encoding.Encode(items.Index(items.Len() - 1).Interface())
+	if err != nil {
+		return 0, fmt.Errorf("rethinkdb: unable to encode last document: %w", err)
+	}
+	values := make([]interface{}, len(fields))
+	for i, f := range fields {
+		v, ok := lookup(encoded, strings.Split(f.Field, "."))
+		if !ok {
+			return 0, fmt.Errorf("rethinkdb: unable to extract sort key '%s'", f.Field)
+		}
+		values[i] = v
+	}
+
+	return items.Len(), pagination.SetNext(values, hasNext)
+}
+
+// lookup returns the value located at the given path of an encoded document.
+func lookup(doc interface{}, path []string) (interface{}, bool) {
+	for _, key := range path {
+		m, ok := doc.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if doc, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return doc, true
+}
+
+// noModification returns the error raised when an update matched nothing, a
+// version conflict is raised if the filter matches without expected version.
+func (d *Default) noModification(ctx context.Context, filter, expected interface{}) error {
+	if d.versionField == "" {
+		return db.ErrNoModification
+	}
+
+	count, err := d.WhereCount(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("rethinkdb: unable to check version conflict: %w", err)
+	}
+	if count > 0 {
+		return db.VersionConflict(d.table, expected)
+	}
+
+	return db.ErrNoModification
+}
+
+// sorts checks the given sort parameters against sortable fields, and adds
+// the primary key as tie-breaker.
+func (d *Default) sorts(params *db.SortParameters) (db.SortParameters, error) {
+	if params == nil {
+		params = &db.SortParameters{}
+	}
+
+	// Check sortable fields
+	if err := params.Validate(func(field string) bool {
+		return len(d.sortableFields) == 0 || field == d.primaryKey || d.sortableFields[field]
+	}); err != nil {
+		return nil, err
+	}
+
+	return params.TieBreak(d.primaryKey), nil
+}
+
+// where returns the table term filtered by the given filter if any,
+// backend-neutral criteria are converted to rethinkdb filter.
+func (d *Default) where(filter interface{}) (r.Term, error) {
+	return d.filter(r.Table(d.table), filter)
+}
+
+// filter applies the given filter to the term.
+func (d *Default) filter(term r.Term, filter interface{}) (r.Term, error) {
+	// Exclude deleted documents
+	if d.tracking.SoftDelete() {
+		term = term.Filter(r.Row.Field(d.tracking.DeletedAt).Default(nil).Eq(nil))
+	}
+
+	switch f := filter.(type) {
+	case nil:
+		return term, nil
+	case *db.Criterion:
+		c, err := ConvertCriteria(f, d.filterableFields)
+		if err != nil {
+			return r.Term{}, err
+		}
+		return term.Filter(c), nil
+	default:
+		return term.Filter(filter), nil
+	}
+}
+
+// track returns the given document with tracking fields set.
+func (d *Default) track(data interface{}, values map[string]interface{}) (interface{}, error) {
+	if len(values) == 0 {
+		return data, nil
+	}
+
+	// Convert to a generic document
+	encoded, err := encoding.Encode(data)
+	if err != nil {
+		return nil, fmt.Errorf("rethinkdb: unable to encode document: %w", err)
+	}
+	doc, ok := encoded.(map[string]interface{})
+	if !ok {
+		return nil, errors.Newf(errors.InvalidArgument, nil, "rethinkdb: document '%T' must be an object", data)
+	}
+
+	for k, v := range values {
+		doc[k] = v
+	}
+
+	return doc, nil
 }
@@ -0,0 +1,71 @@
+package rethinkdb
+
+import (
+	"fmt"
+	"strings"
+
+	"go.zenithar.org/pkg/db"
+	"go.zenithar.org/pkg/errors"
+
+	"golang.org/x/xerrors"
+	r "gopkg.in/rethinkdb/rethinkdb-go.v6"
+)
+
+// ErrorCode returns the code of the given error, driver errors are classified
+// using their type.
+func ErrorCode(err error) errors.ErrorCode {
+	return db.ErrorCode(err, classify)
+}
+
+// wrapError returns the given error wrapped with the message and classified,
+// driver no result errors are replaced by db.ErrNoResult.
+func wrapError(err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	if xerrors.Is(err, r.ErrEmptyResult) {
+		err = db.ErrNoResult
+	}
+
+	return errors.New(ErrorCode(err), err, 2, fmt.Sprintf(format, args...))
+}
+
+// -----------------------------------------------------------------------------
+
+func classify(err error) errors.ErrorCode {
+	var (
+		nonExistenceErr  r.RQLNonExistenceError
+		queryLogicErr    r.RQLQueryLogicError
+		resourceLimitErr r.RQLResourceLimitError
+		userErr          r.RQLUserError
+		timeoutErr       r.RQLTimeoutError
+		availabilityErr  r.RQLAvailabilityError
+		opFailedErr      r.RQLOpFailedError
+		opIndeterminate  r.RQLOpIndeterminateError
+		authErr          r.RQLAuthError
+		connectionErr    r.RQLConnectionError
+	)
+
+	switch {
+	case xerrors.Is(err, r.ErrEmptyResult), xerrors.As(err, &nonExistenceErr):
+		return errors.NotFound
+	case strings.Contains(err.Error(), "Duplicate primary key"):
+		// Write errors are only reported as messages
+		return errors.AlreadyExists
+	case xerrors.As(err, &queryLogicErr):
+		return errors.InvalidArgument
+	case xerrors.As(err, &resourceLimitErr):
+		return errors.ResourceExhausted
+	case xerrors.As(err, &userErr):
+		return errors.FailedPrecondition
+	case xerrors.Is(err, r.ErrQueryTimeout), xerrors.As(err, &timeoutErr):
+		return errors.DeadlineExceeded
+	case xerrors.As(err, &authErr):
+		return errors.Unauthenticated
+	case xerrors.As(err, &availabilityErr), xerrors.As(err, &opFailedErr), xerrors.As(err, &opIndeterminate),
+		xerrors.As(err, &connectionErr), xerrors.Is(err, r.ErrConnectionClosed), xerrors.Is(err, r.ErrNoConnections):
+		return errors.Unavailable
+	}
+
+	return errors.Unknown
+}
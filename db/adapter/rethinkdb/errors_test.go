@@ -0,0 +1,56 @@
+package rethinkdb
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/xerrors"
+	r "gopkg.in/rethinkdb/rethinkdb-go.v6"
+
+	"go.zenithar.org/pkg/db"
+	"go.zenithar.org/pkg/errors"
+)
+
+func TestErrorCode(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want errors.ErrorCode
+	}{
+		{name: "nil", err: nil, want: errors.OK},
+		{name: "generic error", err: fmt.Errorf("foo"), want: errors.Unknown},
+		{name: "empty result", err: r.ErrEmptyResult, want: errors.NotFound},
+		{name: "duplicate primary key", err: fmt.Errorf("Duplicate primary key `id`: {...}"), want: errors.AlreadyExists},
+		{name: "query timeout", err: r.ErrQueryTimeout, want: errors.DeadlineExceeded},
+		{name: "connection closed", err: fmt.Errorf("rethinkdb: %w", r.ErrConnectionClosed), want: errors.Unavailable},
+		{name: "connection error", err: r.RQLConnectionError{}, want: errors.Unavailable},
+		{name: "version conflict", err: db.VersionConflict("users", 1), want: errors.Aborted},
+	}
+
+	for _, tc := range testCases {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := ErrorCode(tt.err); got != tt.want {
+				t.Fatalf("got %v, wanted %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapError(t *testing.T) {
+	if wrapError(nil, "rethinkdb: unable to execute query") != nil {
+		t.Fatalf("error must not be raised")
+	}
+
+	err := wrapError(r.ErrEmptyResult, "rethinkdb: unable to retrieve query result")
+
+	var e *errors.Error
+	if !xerrors.As(err, &e) || e.Code != errors.NotFound {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+	if !xerrors.Is(err, db.ErrNoResult) {
+		t.Fatalf("error must match db.ErrNoResult")
+	}
+}
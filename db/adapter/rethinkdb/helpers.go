@@ -8,20 +8,18 @@ import (
 	r "gopkg.in/rethinkdb/rethinkdb-go.v6"
 )
 
-// ConvertSortParameters to rethinkdb query string
+// ConvertSortParameters to rethinkdb query string, sort order is preserved.
 func ConvertSortParameters(params db.SortParameters) []interface{} {
 
 	var sorts []interface{}
-	for k, v := range params {
-		switch v {
+	for _, f := range params {
+		switch f.Direction {
 		case db.Ascending:
-			sorts = append(sorts, r.Asc(strings.ToLower(k)))
-			break
+			sorts = append(sorts, r.Asc(strings.ToLower(f.Field)))
 		case db.Descending:
-			sorts = append(sorts, r.Desc(strings.ToLower(k)))
-			break
+			sorts = append(sorts, r.Desc(strings.ToLower(f.Field)))
 		default:
-			sorts = append(sorts, r.Desc(strings.ToLower(k)))
+			sorts = append(sorts, r.Desc(strings.ToLower(f.Field)))
 		}
 	}
 
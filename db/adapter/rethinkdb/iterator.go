@@ -0,0 +1,75 @@
+package rethinkdb
+
+import (
+	"context"
+	"fmt"
+
+	"go.zenithar.org/pkg/db"
+
+	r "gopkg.in/rethinkdb/rethinkdb-go.v6"
+	"gopkg.in/rethinkdb/rethinkdb-go.v6/encoding"
+)
+
+var _ db.Iterable = (*Default)(nil)
+
+// Iterate returns an iterator over documents matching the given filter,
+// documents are streamed from the database.
+func (d *Default) Iterate(ctx context.Context, filter interface{}, sortParams *db.SortParameters) (db.Iterator, error) {
+	// Check sort parameters
+	sorts, err := d.sorts(sortParams)
+	if err != nil {
+		return nil, err
+	}
+
+	// Primary key order uses the primary index to stream results, other sorts
+	// are subject to the server array size limit.
+	term := r.Table(d.table)
+	if len(sorts) == 1 {
+		term = term.OrderBy(r.OrderByOpts{Index: ConvertSortParameters(sorts)[0]})
+	}
+
+	term, err = d.filter(term, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(sorts) > 1 {
+		term = term.OrderBy(ConvertSortParameters(sorts)...)
+	}
+
+	// Run the query
+	cursor, err := term.Run(d.session, r.RunOpts{
+		Context: ctx,
+	})
+	if err != nil {
+		return nil, wrapError(err, "rethinkdb: unable to execute query")
+	}
+
+	return &iterator{cursor: cursor}, nil
+}
+
+// -----------------------------------------------------------------------------
+
+type iterator struct {
+	cursor  *r.Cursor
+	current interface{}
+}
+
+func (it *iterator) Next() bool {
+	it.current = nil
+	return it.cursor.Next(&it.current)
+}
+
+func (it *iterator) Scan(dest interface{}) error {
+	if err := encoding.Decode(dest, it.current); err != nil {
+		return fmt.Errorf("rethinkdb: unable to decode document: %w", err)
+	}
+	return nil
+}
+
+func (it *iterator) Err() error {
+	return it.cursor.Err()
+}
+
+func (it *iterator) Close() error {
+	return it.cursor.Close()
+}
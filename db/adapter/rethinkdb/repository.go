@@ -0,0 +1,45 @@
+package rethinkdb
+
+import (
+	"context"
+
+	"go.zenithar.org/pkg/db"
+)
+
+var _ db.Repository = (*repository)(nil)
+
+// Repository returns the table as a db.Repository, Default methods keep
+// their historical signatures.
+func (d *Default) Repository() db.Repository {
+	return &repository{d: d}
+}
+
+// -----------------------------------------------------------------------------
+
+type repository struct {
+	d *Default
+}
+
+func (r *repository) Create(ctx context.Context, data interface{}) error {
+	return r.d.Create(ctx, data)
+}
+
+func (r *repository) WhereCount(ctx context.Context, filter interface{}) (int, error) {
+	return r.d.WhereCount(ctx, filter)
+}
+
+func (r *repository) WhereAndFetchOne(ctx context.Context, filter interface{}, result interface{}) error {
+	return r.d.WhereAndFetchOne(ctx, filter, result)
+}
+
+func (r *repository) Update(ctx context.Context, updates map[string]interface{}, filter interface{}) error {
+	return r.d.UpdateWhere(ctx, updates, filter)
+}
+
+func (r *repository) RemoveOne(ctx context.Context, filter interface{}) error {
+	return r.d.RemoveOne(ctx, filter)
+}
+
+func (r *repository) Search(ctx context.Context, filter interface{}, pagination *db.Pagination, sortParams *db.SortParameters, results interface{}) (int, error) {
+	return r.d.search(ctx, filter, pagination, sortParams, results)
+}
@@ -0,0 +1,112 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.zenithar.org/pkg/log"
+
+	"github.com/dchest/uniuri"
+	"github.com/jmoiron/sqlx"
+	"github.com/opencensus-integrations/ocsql"
+	"go.uber.org/zap"
+
+	// Load sqlite driver
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Configuration represents database connection configuration
+type Configuration struct {
+	ConnectionString string        `toml:"connectionString" default:"file::memory:?cache=shared" comment:"Database file path or URI, anonymous in-memory databases are private to the connection pool"`
+	ForeignKeys      bool          `toml:"foreignKeys" default:"true" comment:"Enforce foreign key constraints"`
+	BusyTimeout      time.Duration `toml:"busyTimeout" default:"5s" comment:"Time to wait for a locked database before failing"`
+	MaxOpenConns     int           `toml:"maxOpenConns" default:"1" comment:"Maximum number of open connections (1 if 0, negative for unlimited)"`
+}
+
+// Connection provides Wire provider for a SQLite database connection, the
+// connection is closed when the context is done.
+func Connection(ctx context.Context, cfg *Configuration) (*sqlx.DB, error) {
+	// Instrument with opentracing
+	driverName, err := ocsql.Register(
+		"sqlite3",
+		ocsql.WithOptions(ocsql.TraceOptions{
+			AllowRoot:    false,
+			Ping:         true,
+			RowsNext:     true,
+			RowsClose:    true,
+			RowsAffected: true,
+			LastInsertID: true,
+			Query:        true,
+			QueryParams:  false,
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: failed to register ocsql driver: %w", err)
+	}
+
+	// Open database
+	conn, err := sqlx.Open(driverName, dsn(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: unable to open driver: %w", err)
+	}
+
+	// Check connection
+	if err := conn.PingContext(ctx); err != nil {
+		log.SafeClose(conn, "Unable to close database connection")
+		return nil, fmt.Errorf("sqlite: unable to open database: %w", err)
+	}
+
+	// Update connection pool settings, in-memory databases only live as long
+	// as their connection so it must never expire.
+	maxOpenConns := cfg.MaxOpenConns
+	if maxOpenConns == 0 {
+		maxOpenConns = 1
+	}
+	conn.SetConnMaxLifetime(0)
+	conn.SetMaxIdleConns(maxOpenConns)
+	conn.SetMaxOpenConns(maxOpenConns)
+
+	log.For(ctx).Info("SQLite connected !", zap.String("path", cfg.ConnectionString))
+
+	stopStats := ocsql.RecordStats(conn.DB, 5*time.Second)
+	go func() {
+		<-ctx.Done()
+		stopStats()
+		log.SafeClose(conn, "Unable to close database connection")
+	}()
+
+	// Return connection
+	return conn, nil
+}
+
+// -----------------------------------------------------------------------------
+
+// dsn returns the connection string with driver pragmas applied. Anonymous
+// shared-cache in-memory databases are named uniquely, so that connections
+// opened with the default configuration don't share the same database.
+func dsn(cfg *Configuration) string {
+	busyTimeout := cfg.BusyTimeout
+	if busyTimeout == 0 {
+		busyTimeout = 5 * time.Second
+	}
+
+	params := url.Values{}
+	params.Set("_foreign_keys", fmt.Sprintf("%t", cfg.ForeignKeys))
+	params.Set("_busy_timeout", fmt.Sprintf("%d", busyTimeout.Milliseconds()))
+
+	connStr := cfg.ConnectionString
+	if strings.HasPrefix(connStr, "file::memory:") {
+		params.Set("mode", "memory")
+		connStr = fmt.Sprintf("file:memdb-%s%s", uniuri.New(), strings.TrimPrefix(connStr, "file::memory:"))
+	}
+
+	sep := "?"
+	if strings.Contains(connStr, "?") {
+		sep = "&"
+	}
+
+	return connStr + sep + params.Encode()
+}
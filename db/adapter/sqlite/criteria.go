@@ -0,0 +1,26 @@
+package sqlite
+
+import (
+	"go.zenithar.org/pkg/db"
+	"go.zenithar.org/pkg/db/adapter/internal/sqlcrud"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// ConvertCriteria converts the given criterion to a sql filter, fields are
+// converted to snake case and must be part of filterable columns.
+func ConvertCriteria(c *db.Criterion, filterableColumns map[string]bool) (sq.Sqlizer, error) {
+	return sqlcrud.ConvertCriteria(c, filterableColumns)
+}
+
+// ConvertSortParameters to sql query string, sort order is preserved and
+// columns not part of sortable ones are ignored.
+func ConvertSortParameters(params db.SortParameters, sortableColumns map[string]bool) []string {
+	return sqlcrud.ConvertSortParameters(params, sortableColumns)
+}
+
+// ToSnakeCase convert the given string to snake case following the Golang format:
+// acronyms are converted to lower-case and preceded by an underscore.
+func ToSnakeCase(in string) string {
+	return sqlcrud.ToSnakeCase(in)
+}
@@ -0,0 +1,88 @@
+package sqlite
+
+import (
+	"context"
+
+	"go.zenithar.org/pkg/db"
+	"go.zenithar.org/pkg/db/adapter/internal/sqlcrud"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+var _ db.Repository = (*Default)(nil)
+
+// dialect describes SQLite specific CRUD behaviors.
+var dialect = sqlcrud.Dialect{
+	Name:        "sqlite",
+	Placeholder: sq.Question,
+	WrapError:   wrapError,
+}
+
+// Default contains the basic implementation of the SQL interface
+type Default struct {
+	crud *sqlcrud.Table
+}
+
+// NewCRUDTable sets up a new Default struct
+func NewCRUDTable(session *sqlx.DB, db, table string, columns, sortable []string, opts ...Option) *Default {
+	d := &Default{
+		crud: sqlcrud.New(dialect, session, db, table, columns, sortable),
+	}
+
+	// Apply options
+	for _, o := range opts {
+		o(d)
+	}
+
+	return d
+}
+
+// -----------------------------------------------------------------------------
+
+// GetTableName returns table's name
+func (d *Default) GetTableName() string {
+	return d.crud.GetTableName()
+}
+
+// GetDBName returns database's name
+func (d *Default) GetDBName() string {
+	return d.crud.GetDBName()
+}
+
+// GetSession returns the current session
+func (d *Default) GetSession() interface{} {
+	return d.crud.GetSession()
+}
+
+// -----------------------------------------------------------------------------
+
+// Create a record
+func (d *Default) Create(ctx context.Context, data interface{}) error {
+	return d.crud.Create(ctx, data)
+}
+
+// WhereCount is used to cound resultset elements from the given filter
+func (d *Default) WhereCount(ctx context.Context, filter interface{}) (int, error) {
+	return d.crud.WhereCount(ctx, filter)
+}
+
+// WhereAndFetchOne returns only one element from the given filter
+func (d *Default) WhereAndFetchOne(ctx context.Context, filter interface{}, result interface{}) error {
+	return d.crud.WhereAndFetchOne(ctx, filter, result)
+}
+
+// Update the collection element with updates set matching the given filter
+func (d *Default) Update(ctx context.Context, updates map[string]interface{}, filter interface{}) error {
+	return d.crud.Update(ctx, updates, filter)
+}
+
+// RemoveOne is used to remove one element from the collection that match the filter
+func (d *Default) RemoveOne(ctx context.Context, filter interface{}) error {
+	return d.crud.RemoveOne(ctx, filter)
+}
+
+// Search for element in collection
+func (d *Default) Search(ctx context.Context, filter interface{}, pagination *db.Pagination, sortParams *db.SortParameters, results interface{}) (int, error) {
+	return d.crud.Search(ctx, filter, pagination, sortParams, results)
+}
@@ -0,0 +1,285 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/xerrors"
+
+	"go.zenithar.org/pkg/db"
+	"go.zenithar.org/pkg/errors"
+	"go.zenithar.org/pkg/types"
+)
+
+type user struct {
+	ID   string `db:"id"`
+	Name string `db:"name"`
+	Age  int    `db:"age"`
+}
+
+func setup(t *testing.T) *Default {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	conn, err := Connection(ctx, &Configuration{
+		ConnectionString: fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()),
+		MaxOpenConns:     1,
+	})
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if _, err := conn.ExecContext(ctx, "CREATE TABLE users (id TEXT PRIMARY KEY, name TEXT NOT NULL, age INTEGER NOT NULL)"); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+
+	underTest := NewCRUDTable(conn, "main", "users", []string{"id", "name", "age"}, []string{"name", "age"})
+	for i, name := range []string{"alice", "bob", "carol", "dave", "eve"} {
+		if err := underTest.Create(ctx, &user{ID: fmt.Sprintf("%d", i+1), Name: name, Age: 20 + i}); err != nil {
+			t.Fatalf("error must not be raised, got %v", err)
+		}
+	}
+
+	return underTest
+}
+
+func TestDefault_CRUD(t *testing.T) {
+	ctx := context.Background()
+	underTest := setup(t)
+
+	// Duplicate primary key
+	err := underTest.Create(ctx, &user{ID: "1", Name: "mallory"})
+	var e *errors.Error
+	if !xerrors.As(err, &e) || e.Code != errors.AlreadyExists {
+		t.Fatalf("expected already exists error, got %v", err)
+	}
+
+	// Fetch one
+	var got user
+	if err := underTest.WhereAndFetchOne(ctx, db.Eq("name", "bob"), &got); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if diff := cmp.Diff(user{ID: "2", Name: "bob", Age: 21}, got); diff != "" {
+		t.Errorf("%s", diff)
+	}
+	if err := underTest.WhereAndFetchOne(ctx, db.Eq("name", "mallory"), &got); !xerrors.Is(err, db.ErrNoResult) {
+		t.Fatalf("expected no result error, got %v", err)
+	}
+
+	// Update
+	if err := underTest.Update(ctx, map[string]interface{}{"age": 42}, map[string]interface{}{"id": "2"}); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	count, err := underTest.WhereCount(ctx, db.Gte("age", 42))
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if count != 1 {
+		t.Errorf("got %d, wanted 1", count)
+	}
+	if err := underTest.Update(ctx, map[string]interface{}{"age": 42}, map[string]interface{}{"id": "42"}); !xerrors.Is(err, db.ErrNoModification) {
+		t.Fatalf("expected no modification error, got %v", err)
+	}
+
+	// Remove
+	if err := underTest.RemoveOne(ctx, db.Eq("id", "2")); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if err := underTest.RemoveOne(ctx, db.Eq("id", "2")); !xerrors.Is(err, db.ErrNoModification) {
+		t.Fatalf("expected no modification error, got %v", err)
+	}
+
+	// Unsupported filter
+	if _, err := underTest.WhereCount(ctx, 42); err == nil {
+		t.Fatalf("expected error mst be raised")
+	}
+}
+
+func TestDefault_Search(t *testing.T) {
+	ctx := context.Background()
+	underTest := setup(t)
+
+	// Offset pagination
+	var results []user
+	pagination := db.NewPaginator(2, 2)
+	count, err := underTest.Search(ctx, db.Gt("age", 20), pagination, db.SortConverter([]string{"-age"}), &results)
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if count != 4 || pagination.Total() != 4 {
+		t.Errorf("got %d, wanted 4", count)
+	}
+	if diff := cmp.Diff([]user{{ID: "3", Name: "carol", Age: 22}, {ID: "2", Name: "bob", Age: 21}}, results); diff != "" {
+		t.Errorf("%s", diff)
+	}
+
+	// Keyset pagination
+	codec := db.NewCursorCodec([]byte("secret"))
+	var names []string
+	cursor := ""
+	for {
+		pagination, err := db.NewCursorPaginator(codec, cursor, 2)
+		if err != nil {
+			t.Fatalf("error must not be raised, got %v", err)
+		}
+
+		var page []user
+		if _, err := underTest.Search(ctx, nil, pagination, db.SortConverter([]string{"name"}), &page); err != nil {
+			t.Fatalf("error must not be raised, got %v", err)
+		}
+		for _, u := range page {
+			names = append(names, u.Name)
+		}
+
+		if cursor = pagination.NextCursor(); cursor == "" {
+			break
+		}
+	}
+	if diff := cmp.Diff([]string{"alice", "bob", "carol", "dave", "eve"}, names); diff != "" {
+		t.Errorf("%s", diff)
+	}
+
+	// Cursor reused with other sort parameters
+	first, _ := db.NewCursorPaginator(codec, "", 2)
+	if _, err := underTest.Search(ctx, nil, first, db.SortConverter([]string{"name"}), &results); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	next, err := db.NewCursorPaginator(codec, first.NextCursor(), 2)
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if _, err := underTest.Search(ctx, nil, next, db.SortConverter([]string{"-name"}), &results); err == nil {
+		t.Fatalf("expected error mst be raised")
+	}
+
+	// Not sortable column
+	if _, err := underTest.Search(ctx, nil, nil, db.SortConverter([]string{"unknown"}), &results); err == nil {
+		t.Fatalf("expected error mst be raised")
+	}
+
+	// No result
+	if _, err := underTest.Search(ctx, db.Eq("name", "mallory"), nil, nil, &results); !xerrors.Is(err, db.ErrNoResult) {
+		t.Fatalf("expected no result error, got %v", err)
+	}
+}
+
+type draft struct {
+	ID      string `db:"id"`
+	Title   string `db:"title"`
+	Version int    `db:"version"`
+}
+
+type document struct {
+	ID        string         `db:"id"`
+	Title     string         `db:"title"`
+	Version   int            `db:"version"`
+	CreatedAt sql.NullTime   `db:"created_at"`
+	UpdatedAt sql.NullTime   `db:"updated_at"`
+	DeletedAt sql.NullTime   `db:"deleted_at"`
+	CreatedBy sql.NullString `db:"created_by"`
+	UpdatedBy sql.NullString `db:"updated_by"`
+}
+
+func TestDefault_Tracking(t *testing.T) {
+	ctx, cancel := context.WithCancel(types.WithActor(context.Background(), "alice"))
+	defer cancel()
+
+	conn, err := Connection(ctx, &Configuration{
+		ConnectionString: fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()),
+	})
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if _, err := conn.ExecContext(ctx, `CREATE TABLE documents (
+	id         TEXT PRIMARY KEY,
+	title      TEXT NOT NULL,
+	version    INTEGER NOT NULL,
+	created_at TIMESTAMP,
+	updated_at TIMESTAMP,
+	deleted_at TIMESTAMP,
+	created_by TEXT,
+	updated_by TEXT
+)`); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+
+	columns := []string{"id", "title", "version", "created_at", "updated_at", "deleted_at", "created_by", "updated_by"}
+	underTest := NewCRUDTable(conn, "main", "documents", columns, nil,
+		WithVersionColumn("version"),
+		WithTimestamps("created_at", "updated_at"),
+		WithSoftDelete("deleted_at"),
+		WithAudit("created_by", "updated_by"),
+	)
+
+	// Create
+	if err := underTest.Create(ctx, &draft{ID: "1", Title: "draft", Version: 1}); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	var got document
+	if err := underTest.WhereAndFetchOne(ctx, db.Eq("id", "1"), &got); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if !got.CreatedAt.Valid || !got.UpdatedAt.Valid || got.CreatedBy.String != "alice" {
+		t.Fatalf("tracking columns must be set on creation, got %+v", got)
+	}
+
+	// Update with expected version
+	if err := underTest.Update(types.WithActor(ctx, "bob"), map[string]interface{}{"title": "final", "version": 1}, db.Eq("id", "1")); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if err := underTest.WhereAndFetchOne(ctx, db.Eq("id", "1"), &got); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if got.Title != "final" || got.Version != 2 || got.UpdatedBy.String != "bob" {
+		t.Fatalf("update must increment version and track actor, got %+v", got)
+	}
+
+	// Stale version
+	err = underTest.Update(ctx, map[string]interface{}{"title": "stale", "version": 1}, db.Eq("id", "1"))
+	var e *errors.Error
+	if !xerrors.As(err, &e) || e.Code != errors.Aborted {
+		t.Fatalf("expected version conflict error, got %v", err)
+	}
+
+	// Missing version
+	if err := underTest.Update(ctx, map[string]interface{}{"title": "stale"}, db.Eq("id", "1")); err == nil {
+		t.Fatalf("expected error mst be raised")
+	}
+
+	// Soft delete
+	if err := underTest.RemoveOne(ctx, db.Eq("id", "1")); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if err := underTest.WhereAndFetchOne(ctx, db.Eq("id", "1"), &got); !xerrors.Is(err, db.ErrNoResult) {
+		t.Fatalf("expected no result error, got %v", err)
+	}
+	if err := underTest.RemoveOne(ctx, db.Eq("id", "1")); !xerrors.Is(err, db.ErrNoModification) {
+		t.Fatalf("expected no modification error, got %v", err)
+	}
+
+	var deleted int
+	if err := conn.GetContext(ctx, &deleted, "SELECT COUNT(*) FROM documents WHERE deleted_at IS NOT NULL"); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if deleted != 1 {
+		t.Fatalf("row must be marked as deleted")
+	}
+}
+
+func TestConnection_Memory(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Default configuration databases must be isolated
+	for i := 0; i < 2; i++ {
+		conn, err := Connection(ctx, &Configuration{ConnectionString: "file::memory:?cache=shared"})
+		if err != nil {
+			t.Fatalf("error must not be raised, got %v", err)
+		}
+		if _, err := conn.ExecContext(ctx, "CREATE TABLE users (id TEXT PRIMARY KEY)"); err != nil {
+			t.Fatalf("error must not be raised, got %v", err)
+		}
+	}
+}
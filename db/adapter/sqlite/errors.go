@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+
+	"go.zenithar.org/pkg/db"
+	"go.zenithar.org/pkg/errors"
+
+	"github.com/mattn/go-sqlite3"
+	"golang.org/x/xerrors"
+)
+
+// ErrorCode returns the code of the given error, sqlite errors are classified
+// using their result code.
+func ErrorCode(err error) errors.ErrorCode {
+	return db.ErrorCode(err, classify)
+}
+
+// wrapError returns the given error wrapped with the message and classified,
+// driver no result errors are replaced by db.ErrNoResult.
+func wrapError(err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	if xerrors.Is(err, sql.ErrNoRows) {
+		err = db.ErrNoResult
+	}
+
+	return errors.New(ErrorCode(err), err, 2, fmt.Sprintf(format, args...))
+}
+
+// -----------------------------------------------------------------------------
+
+func classify(err error) errors.ErrorCode {
+	if xerrors.Is(err, sql.ErrNoRows) {
+		return errors.NotFound
+	}
+
+	var sqliteErr sqlite3.Error
+	if !xerrors.As(err, &sqliteErr) {
+		return errors.Unknown
+	}
+
+	switch sqliteErr.Code {
+	case sqlite3.ErrConstraint:
+		switch sqliteErr.ExtendedCode {
+		case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+			return errors.AlreadyExists
+		default:
+		}
+		return errors.FailedPrecondition
+	case sqlite3.ErrBusy, sqlite3.ErrLocked:
+		return errors.Aborted
+	case sqlite3.ErrInterrupt:
+		return errors.Canceled
+	case sqlite3.ErrPerm, sqlite3.ErrReadonly, sqlite3.ErrAuth:
+		return errors.PermissionDenied
+	case sqlite3.ErrFull, sqlite3.ErrNomem, sqlite3.ErrTooBig:
+		return errors.ResourceExhausted
+	case sqlite3.ErrCantOpen, sqlite3.ErrIoErr:
+		return errors.Unavailable
+	case sqlite3.ErrMismatch, sqlite3.ErrRange:
+		return errors.InvalidArgument
+	default:
+	}
+
+	return errors.Internal
+}
@@ -0,0 +1,58 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/mattn/go-sqlite3"
+	"golang.org/x/xerrors"
+
+	"go.zenithar.org/pkg/db"
+	"go.zenithar.org/pkg/errors"
+)
+
+func TestErrorCode(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want errors.ErrorCode
+	}{
+		{name: "nil", err: nil, want: errors.OK},
+		{name: "generic error", err: fmt.Errorf("foo"), want: errors.Unknown},
+		{name: "no rows", err: sql.ErrNoRows, want: errors.NotFound},
+		{name: "no result", err: db.ErrNoResult, want: errors.NotFound},
+		{name: "unique violation", err: sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintUnique}, want: errors.AlreadyExists},
+		{name: "primary key violation", err: sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintPrimaryKey}, want: errors.AlreadyExists},
+		{name: "foreign key violation", err: sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintForeignKey}, want: errors.FailedPrecondition},
+		{name: "busy", err: fmt.Errorf("sqlite: %w", sqlite3.Error{Code: sqlite3.ErrBusy}), want: errors.Aborted},
+		{name: "readonly", err: sqlite3.Error{Code: sqlite3.ErrReadonly}, want: errors.PermissionDenied},
+		{name: "context deadline", err: context.DeadlineExceeded, want: errors.DeadlineExceeded},
+		{name: "syntax error", err: sqlite3.Error{Code: sqlite3.ErrError}, want: errors.Internal},
+	}
+
+	for _, tc := range testCases {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := ErrorCode(tt.err); got != tt.want {
+				t.Fatalf("got %v, wanted %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapError(t *testing.T) {
+	// Driver no result errors are replaced
+	err := wrapError(sql.ErrNoRows, "sqlite: unable to execute query")
+	if !xerrors.Is(err, db.ErrNoResult) {
+		t.Fatalf("expected no result error, got %v", err)
+	}
+
+	// Nil errors are kept
+	if err := wrapError(nil, "sqlite: unable to execute query"); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+}
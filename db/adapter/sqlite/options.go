@@ -0,0 +1,50 @@
+package sqlite
+
+import "go.zenithar.org/pkg/db/adapter/internal/sqlcrud"
+
+// Option defines table option builder.
+type Option func(*Default)
+
+// WithFilterableColumns sets the columns allowed in backend-neutral criteria,
+// all selected columns are allowed by default.
+func WithFilterableColumns(columns ...string) Option {
+	return crudOption(sqlcrud.WithFilterableColumns(columns...))
+}
+
+// WithPrimaryKey sets the primary key column used as keyset pagination
+// tie-breaker, 'id' by default.
+func WithPrimaryKey(column string) Option {
+	return crudOption(sqlcrud.WithPrimaryKey(column))
+}
+
+// WithVersionColumn enables optimistic concurrency control using the given
+// column, updates must contain the expected version which is incremented.
+func WithVersionColumn(column string) Option {
+	return crudOption(sqlcrud.WithVersionColumn(column))
+}
+
+// WithTimestamps sets the columns automatically set to the current time on
+// creation and update.
+func WithTimestamps(createdAt, updatedAt string) Option {
+	return crudOption(sqlcrud.WithTimestamps(createdAt, updatedAt))
+}
+
+// WithSoftDelete marks removed rows by setting the given timestamp column
+// instead of deleting them, marked rows are excluded from all queries.
+func WithSoftDelete(deletedAt string) Option {
+	return crudOption(sqlcrud.WithSoftDelete(deletedAt))
+}
+
+// WithAudit sets the columns recording the acting principal from context on
+// creation and update.
+func WithAudit(createdBy, updatedBy string) Option {
+	return crudOption(sqlcrud.WithAudit(createdBy, updatedBy))
+}
+
+// -----------------------------------------------------------------------------
+
+func crudOption(o sqlcrud.Option) Option {
+	return func(d *Default) {
+		o(d.crud)
+	}
+}
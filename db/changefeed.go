@@ -0,0 +1,51 @@
+package db
+
+import "context"
+
+// ChangeType is the enumeration for data change types
+type ChangeType int
+
+const (
+	// ChangeInsert is raised when a record is created
+	ChangeInsert ChangeType = iota + 1
+	// ChangeUpdate is raised when a record is modified
+	ChangeUpdate
+	// ChangeDelete is raised when a record is removed
+	ChangeDelete
+)
+
+var changeTypes = [...]string{
+	"insert",
+	"update",
+	"delete",
+}
+
+func (c ChangeType) String() string {
+	if c < ChangeInsert || int(c) > len(changeTypes) {
+		return "unknown"
+	}
+	return changeTypes[c-1]
+}
+
+// -----------------------------------------------------------------------------
+
+// Change describes a data change event, values are decoded as generic
+// documents and are nil when not provided by the backend.
+type Change struct {
+	Type        ChangeType
+	Table       string
+	Old         map[string]interface{}
+	New         map[string]interface{}
+	ResumeToken string
+}
+
+// ChangeHandler is the change event handler contract.
+type ChangeHandler func(ctx context.Context, change *Change) error
+
+// ChangeFeed describes data change sources.
+type ChangeFeed interface {
+	// Watch blocks emitting changes to the handler, starting after the given
+	// resume token, or from now for a blank one. It returns when the context
+	// is cancelled or the handler fails.
+	Watch(ctx context.Context, resumeToken string, fn ChangeHandler) error
+}
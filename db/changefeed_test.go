@@ -0,0 +1,22 @@
+package db
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestChangeType_String(t *testing.T) {
+	Convey("Given change types", t, func() {
+		Convey("Then known types should be named", func() {
+			So(ChangeInsert.String(), ShouldEqual, "insert")
+			So(ChangeUpdate.String(), ShouldEqual, "update")
+			So(ChangeDelete.String(), ShouldEqual, "delete")
+		})
+
+		Convey("Then out of range types should not panic", func() {
+			So(ChangeType(0).String(), ShouldEqual, "unknown")
+			So(ChangeType(42).String(), ShouldEqual, "unknown")
+		})
+	})
+}
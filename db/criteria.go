@@ -0,0 +1,212 @@
+package db
+
+import (
+	"regexp"
+	"strings"
+
+	"go.zenithar.org/pkg/errors"
+)
+
+// Operator is the enumeration for criteria operators
+type Operator int
+
+const (
+	// OpEq matches field equal to value
+	OpEq Operator = iota + 1
+	// OpNeq matches field not equal to value
+	OpNeq
+	// OpIn matches field equal to one of values
+	OpIn
+	// OpGt matches field strictly greater than value
+	OpGt
+	// OpGte matches field greater than or equal to value
+	OpGte
+	// OpLt matches field strictly lower than value
+	OpLt
+	// OpLte matches field lower than or equal to value
+	OpLte
+	// OpLike matches field against a SQL LIKE pattern
+	OpLike
+	// OpIsNull matches null or missing field
+	OpIsNull
+	// OpAnd matches when all children match
+	OpAnd
+	// OpOr matches when at least one child matches
+	OpOr
+	// OpNot matches when the child doesn't match
+	OpNot
+)
+
+var operators = [...]string{
+	"eq",
+	"neq",
+	"in",
+	"gt",
+	"gte",
+	"lt",
+	"lte",
+	"like",
+	"isnull",
+	"and",
+	"or",
+	"not",
+}
+
+func (o Operator) String() string {
+	if o < OpEq || int(o) > len(operators) {
+		return "unknown"
+	}
+	return operators[o-1]
+}
+
+// -----------------------------------------------------------------------------
+
+// Criterion is a backend-neutral filter expression node, translated to native
+// filters by database adapters.
+type Criterion struct {
+	Operator Operator
+	Field    string
+	Values   []interface{}
+	Children []*Criterion
+}
+
+// Eq returns a criterion matching field equal to value.
+func Eq(field string, value interface{}) *Criterion {
+	return &Criterion{Operator: OpEq, Field: field, Values: []interface{}{value}}
+}
+
+// Neq returns a criterion matching field not equal to value.
+func Neq(field string, value interface{}) *Criterion {
+	return &Criterion{Operator: OpNeq, Field: field, Values: []interface{}{value}}
+}
+
+// In returns a criterion matching field equal to one of values.
+func In(field string, values ...interface{}) *Criterion {
+	return &Criterion{Operator: OpIn, Field: field, Values: values}
+}
+
+// Gt returns a criterion matching field strictly greater than value.
+func Gt(field string, value interface{}) *Criterion {
+	return &Criterion{Operator: OpGt, Field: field, Values: []interface{}{value}}
+}
+
+// Gte returns a criterion matching field greater than or equal to value.
+func Gte(field string, value interface{}) *Criterion {
+	return &Criterion{Operator: OpGte, Field: field, Values: []interface{}{value}}
+}
+
+// Lt returns a criterion matching field strictly lower than value.
+func Lt(field string, value interface{}) *Criterion {
+	return &Criterion{Operator: OpLt, Field: field, Values: []interface{}{value}}
+}
+
+// Lte returns a criterion matching field lower than or equal to value.
+func Lte(field string, value interface{}) *Criterion {
+	return &Criterion{Operator: OpLte, Field: field, Values: []interface{}{value}}
+}
+
+// Between returns a criterion matching field in the inclusive range [from, to].
+func Between(field string, from, to interface{}) *Criterion {
+	return And(Gte(field, from), Lte(field, to))
+}
+
+// Like returns a criterion matching field against a SQL LIKE pattern, where
+// '%' matches any sequence and '_' matches any single character.
+func Like(field string, pattern string) *Criterion {
+	return &Criterion{Operator: OpLike, Field: field, Values: []interface{}{pattern}}
+}
+
+// IsNull returns a criterion matching null or missing field.
+func IsNull(field string) *Criterion {
+	return &Criterion{Operator: OpIsNull, Field: field}
+}
+
+// And returns a criterion matching when all given criteria match.
+func And(criteria ...*Criterion) *Criterion {
+	return &Criterion{Operator: OpAnd, Children: criteria}
+}
+
+// Or returns a criterion matching when at least one of given criteria matches.
+func Or(criteria ...*Criterion) *Criterion {
+	return &Criterion{Operator: OpOr, Children: criteria}
+}
+
+// Not returns a criterion matching when the given criterion doesn't match.
+func Not(criterion *Criterion) *Criterion {
+	return &Criterion{Operator: OpNot, Children: []*Criterion{criterion}}
+}
+
+// -----------------------------------------------------------------------------
+
+// Validate checks the criterion tree structure, and that all fields are
+// accepted by the given allow-list function.
+func (c *Criterion) Validate(allowed func(field string) bool) error {
+	if c == nil {
+		return errors.Newf(errors.InvalidArgument, nil, "criteria: criterion must not be nil")
+	}
+
+	switch c.Operator {
+	case OpAnd, OpOr, OpNot:
+		if len(c.Children) == 0 {
+			return errors.Newf(errors.InvalidArgument, nil, "criteria: '%s' requires at least one child", c.Operator)
+		}
+		if c.Operator == OpNot && len(c.Children) != 1 {
+			return errors.Newf(errors.InvalidArgument, nil, "criteria: 'not' requires exactly one child")
+		}
+		for _, child := range c.Children {
+			if err := child.Validate(allowed); err != nil {
+				return err
+			}
+		}
+		return nil
+	case OpEq, OpNeq, OpGt, OpGte, OpLt, OpLte, OpLike:
+		if len(c.Values) != 1 {
+			return errors.Newf(errors.InvalidArgument, nil, "criteria: '%s' requires exactly one value", c.Operator)
+		}
+		if _, ok := c.Values[0].(string); c.Operator == OpLike && !ok {
+			return errors.Newf(errors.InvalidArgument, nil, "criteria: 'like' requires a string pattern")
+		}
+	case OpIn:
+		if len(c.Values) == 0 {
+			return errors.Newf(errors.InvalidArgument, nil, "criteria: 'in' requires at least one value")
+		}
+	case OpIsNull:
+		if len(c.Values) != 0 {
+			return errors.Newf(errors.InvalidArgument, nil, "criteria: 'isnull' doesn't accept values")
+		}
+	default:
+		return errors.Newf(errors.InvalidArgument, nil, "criteria: unsupported operator '%d'", c.Operator)
+	}
+
+	// Check field
+	if c.Field == "" {
+		return errors.Newf(errors.InvalidArgument, nil, "criteria: '%s' requires a field", c.Operator)
+	}
+	if allowed == nil || !allowed(c.Field) {
+		return errors.Newf(errors.InvalidArgument, nil, "criteria: field '%s' is not filterable", c.Field)
+	}
+
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+// LikeToRegexp converts a SQL LIKE pattern to an anchored regular expression.
+func LikeToRegexp(pattern string) string {
+	var sb strings.Builder
+
+	sb.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			sb.WriteString(".*")
+		case '_':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+
+	return sb.String()
+}
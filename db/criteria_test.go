@@ -0,0 +1,72 @@
+package db
+
+import (
+	"regexp"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCriteriaValidation(t *testing.T) {
+	allowed := func(field string) bool {
+		return field == "name" || field == "age"
+	}
+
+	Convey("Given a criteria tree", t, func() {
+
+		Convey("When all fields are filterable", func() {
+			c := And(Eq("name", "foo"), Or(Between("age", 18, 25), Not(IsNull("age"))), In("name", "a", "b"), Like("name", "f%"))
+
+			Convey("Then validation should succeed", func() {
+				So(c.Validate(allowed), ShouldBeNil)
+			})
+		})
+
+		Convey("When a field is not filterable", func() {
+			c := And(Eq("name", "foo"), Eq("password", "secret"))
+
+			Convey("Then validation should fail", func() {
+				So(c.Validate(allowed), ShouldNotBeNil)
+			})
+		})
+
+		Convey("When the tree is malformed", func() {
+			Convey("Then validation should fail", func() {
+				So(And().Validate(allowed), ShouldNotBeNil)
+				So(In("name").Validate(allowed), ShouldNotBeNil)
+				So(Like("name", "").Validate(allowed), ShouldBeNil)
+				So((&Criterion{Operator: OpLike, Field: "name", Values: []interface{}{1}}).Validate(allowed), ShouldNotBeNil)
+				So((&Criterion{Operator: OpNot, Children: []*Criterion{IsNull("age"), IsNull("name")}}).Validate(allowed), ShouldNotBeNil)
+				So((&Criterion{Operator: Operator(42), Field: "name"}).Validate(allowed), ShouldNotBeNil)
+				So(Eq("", 1).Validate(allowed), ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestLikeToRegexp(t *testing.T) {
+	Convey("Given a LIKE pattern", t, func() {
+		expr := regexp.MustCompile(LikeToRegexp("f_o%.bar"))
+
+		Convey("Then the regular expression should match the same values", func() {
+			So(expr.MatchString("foo.bar"), ShouldBeTrue)
+			So(expr.MatchString("fao-something.bar"), ShouldBeTrue)
+			So(expr.MatchString("foo-bar"), ShouldBeFalse)
+			So(expr.MatchString("xfoo.bar"), ShouldBeFalse)
+		})
+	})
+}
+
+func TestOperator_String(t *testing.T) {
+	Convey("Given criteria operators", t, func() {
+		Convey("Then known operators should be named", func() {
+			So(OpEq.String(), ShouldEqual, "eq")
+			So(OpNot.String(), ShouldEqual, "not")
+		})
+
+		Convey("Then out of range operators should not panic", func() {
+			So(Operator(0).String(), ShouldEqual, "unknown")
+			So(Operator(42).String(), ShouldEqual, "unknown")
+		})
+	})
+}
@@ -0,0 +1,119 @@
+package db
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.zenithar.org/pkg/errors"
+)
+
+func init() {
+	RegisterCursorType(time.Time{})
+	RegisterCursorType(map[string]interface{}{})
+}
+
+// RegisterCursorType registers a concrete sort key value type used in keyset
+// pagination cursors. Basic types are registered by default.
+func RegisterCursorType(value interface{}) {
+	gob.Register(value)
+}
+
+// -----------------------------------------------------------------------------
+
+// CursorScope returns the digest identifying a keyset query, cursors are only
+// valid for the query they were issued for. Filters are compared using their
+// JSON representation, or their Go representation if not serializable.
+func CursorScope(table string, filter interface{}, fields []SortField) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d:%s", len(table), table)
+
+	for _, f := range fields {
+		fmt.Fprintf(h, "|%s:%s", f.Field, f.Direction)
+	}
+
+	if payload, err := json.Marshal(filter); err == nil {
+		h.Write(payload)
+	} else {
+		fmt.Fprintf(h, "%#v", filter)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// -----------------------------------------------------------------------------
+
+type cursorPayload struct {
+	Scope  string
+	Values []interface{}
+}
+
+// CursorCodec encodes and decodes opaque, tamper-protected pagination cursors.
+type CursorCodec struct {
+	secret []byte
+}
+
+// NewCursorCodec returns a cursor codec using the given secret to sign cursors.
+func NewCursorCodec(secret []byte) *CursorCodec {
+	return &CursorCodec{
+		secret: append([]byte(nil), secret...),
+	}
+}
+
+// Encode the given query scope and sort key values as a signed cursor.
+func (c *CursorCodec) Encode(scope string, values []interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&cursorPayload{Scope: scope, Values: values}); err != nil {
+		return "", errors.Newf(errors.Internal, err, "cursor: unable to encode values")
+	}
+
+	payload := buf.Bytes()
+	return strings.Join([]string{
+		base64.RawURLEncoding.EncodeToString(payload),
+		base64.RawURLEncoding.EncodeToString(c.sign(payload)),
+	}, "."), nil
+}
+
+// Decode the given cursor and returns the query scope and sort key values.
+func (c *CursorCodec) Decode(cursor string) (string, []interface{}, error) {
+	parts := strings.Split(cursor, ".")
+	if len(parts) != 2 {
+		return "", nil, errors.Newf(errors.InvalidArgument, nil, "cursor: invalid format")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", nil, errors.Newf(errors.InvalidArgument, err, "cursor: invalid payload encoding")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", nil, errors.Newf(errors.InvalidArgument, err, "cursor: invalid signature encoding")
+	}
+
+	// Check signature
+	if !hmac.Equal(signature, c.sign(payload)) {
+		return "", nil, errors.Newf(errors.InvalidArgument, nil, "cursor: invalid signature")
+	}
+
+	var decoded cursorPayload
+	if err := gob.NewDecoder(bytes.NewReader(payload)).Decode(&decoded); err != nil {
+		return "", nil, errors.Newf(errors.InvalidArgument, err, "cursor: unable to decode values")
+	}
+
+	return decoded.Scope, decoded.Values, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (c *CursorCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,161 @@
+package db
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCursorCodec(t *testing.T) {
+	Convey("Given a cursor codec", t, func() {
+		codec := NewCursorCodec([]byte("secret"))
+		createdAt := time.Date(2020, 4, 1, 12, 0, 0, 0, time.UTC)
+
+		Convey("When encoding sort key values", func() {
+			cursor, err := codec.Encode("scope", []interface{}{"foo", createdAt, 12})
+			So(err, ShouldBeNil)
+
+			Convey("Then decoding should return the same values", func() {
+				scope, values, err := codec.Decode(cursor)
+				So(err, ShouldBeNil)
+				So(scope, ShouldEqual, "scope")
+				So(values, ShouldHaveLength, 3)
+				So(values[0], ShouldEqual, "foo")
+				So(values[1].(time.Time).Equal(createdAt), ShouldBeTrue)
+				So(values[2], ShouldEqual, 12)
+			})
+
+			Convey("Then decoding with another secret should fail", func() {
+				_, _, err := NewCursorCodec([]byte("other")).Decode(cursor)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Then decoding a tampered cursor should fail", func() {
+				other, err := codec.Encode("scope", []interface{}{"bar", createdAt, 12})
+				So(err, ShouldBeNil)
+
+				_, _, err = codec.Decode(strings.Split(other, ".")[0] + "." + strings.Split(cursor, ".")[1])
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When decoding a malformed cursor", func() {
+			_, _, err := codec.Decode("malformed")
+
+			Convey("Then an error should be raised", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestCursorPaginator(t *testing.T) {
+	Convey("Given a cursor codec", t, func() {
+		codec := NewCursorCodec([]byte("secret"))
+
+		Convey("When creating a paginator without cursor", func() {
+			paginator, err := NewCursorPaginator(codec, "", 20)
+			So(err, ShouldBeNil)
+
+			Convey("Then it should start from the beginning", func() {
+				So(paginator.IsKeyset(), ShouldBeTrue)
+				So(paginator.After(), ShouldBeEmpty)
+				So(paginator.Offset(), ShouldEqual, 0)
+				So(paginator.HasNext(), ShouldBeFalse)
+				So(paginator.NextCursor(), ShouldBeEmpty)
+			})
+
+			Convey("Then the next cursor should resume after the last element", func() {
+				So(paginator.SetNext([]interface{}{"foo", 12}, true), ShouldBeNil)
+				So(paginator.HasNext(), ShouldBeTrue)
+				So(paginator.NextCursor(), ShouldNotBeEmpty)
+
+				next, err := NewCursorPaginator(codec, paginator.NextCursor(), 20)
+				So(err, ShouldBeNil)
+				So(next.After(), ShouldResemble, []interface{}{"foo", 12})
+			})
+
+			Convey("Then the next cursor should be bound to the query", func() {
+				fields := []SortField{{Field: "name", Direction: Ascending}, {Field: "id", Direction: Ascending}}
+				So(paginator.Bind("users", Eq("active", true), fields), ShouldBeNil)
+				So(paginator.SetNext([]interface{}{"foo", 12}, true), ShouldBeNil)
+
+				next, err := NewCursorPaginator(codec, paginator.NextCursor(), 20)
+				So(err, ShouldBeNil)
+				So(next.Bind("users", Eq("active", true), fields), ShouldBeNil)
+
+				for _, scope := range []struct {
+					table  string
+					filter interface{}
+					fields []SortField
+				}{
+					{table: "groups", filter: Eq("active", true), fields: fields},
+					{table: "users", filter: Eq("active", false), fields: fields},
+					{table: "users", filter: Eq("active", true), fields: []SortField{{Field: "name", Direction: Descending}, {Field: "id", Direction: Ascending}}},
+				} {
+					next, err := NewCursorPaginator(codec, paginator.NextCursor(), 20)
+					So(err, ShouldBeNil)
+					So(next.Bind(scope.table, scope.filter, scope.fields), ShouldNotBeNil)
+				}
+			})
+
+			Convey("Then null sort key values should be rejected", func() {
+				So(paginator.SetNext([]interface{}{nil, 12}, true), ShouldNotBeNil)
+			})
+
+			Convey("Then there is no next cursor on the last page", func() {
+				So(paginator.SetNext([]interface{}{"foo", 12}, false), ShouldBeNil)
+				So(paginator.HasNext(), ShouldBeFalse)
+				So(paginator.NextCursor(), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When creating a paginator with an invalid cursor", func() {
+			_, err := NewCursorPaginator(codec, "invalid", 20)
+
+			Convey("Then an error should be raised", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestKeyset(t *testing.T) {
+	Convey("Given sort parameters", t, func() {
+		params := SortConverter([]string{"-created", "name"})
+
+		Convey("When building keyset fields", func() {
+			fields := params.TieBreak("id")
+
+			Convey("Then the primary key should be appended as tie-breaker", func() {
+				So(fields, ShouldResemble, SortParameters{
+					{Field: "created", Direction: Descending},
+					{Field: "name", Direction: Ascending},
+					{Field: "id", Direction: Ascending},
+				})
+			})
+
+			Convey("Then the keyset criterion should match following elements", func() {
+				c, err := KeysetCriterion(fields, []interface{}{10, "foo", "abc"})
+				So(err, ShouldBeNil)
+				So(c, ShouldResemble, Or(
+					And(Lt("created", 10)),
+					And(Eq("created", 10), Gt("name", "foo")),
+					And(Eq("created", 10), Eq("name", "foo"), Gt("id", "abc")),
+				))
+			})
+
+			Convey("Then a cursor with mismatching values should be rejected", func() {
+				_, err := KeysetCriterion(fields, []interface{}{10})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Then a cursor with null values should be rejected", func() {
+				_, err := KeysetCriterion(fields, []interface{}{10, nil, "abc"})
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -1,6 +1,15 @@
 package db
 
-import "golang.org/x/xerrors"
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"net"
+
+	"go.zenithar.org/pkg/errors"
+
+	"golang.org/x/xerrors"
+)
 
 var (
 	// ErrNoResult is raised when data query returns no result
@@ -9,4 +18,41 @@ var (
 	ErrTooManyResults = xerrors.New("too many results returned")
 	// ErrNoModification is raised when updating an entity without any changes
 	ErrNoModification = xerrors.New("No changes made")
+	// ErrVersionConflict is raised when updating an entity modified concurrently
+	ErrVersionConflict = xerrors.New("version conflict")
 )
+
+// ErrorCode returns the code of the given error. Codes of errors.Error values,
+// sentinel errors, context and network errors are resolved first, then the
+// driver classifier is used.
+func ErrorCode(err error, classify func(error) errors.ErrorCode) errors.ErrorCode {
+	var e *errors.Error
+	var netErr net.Error
+
+	switch {
+	case err == nil:
+		return errors.OK
+	case xerrors.As(err, &e):
+		return e.Code
+	case xerrors.Is(err, ErrNoResult), xerrors.Is(err, ErrNoModification):
+		return errors.NotFound
+	case xerrors.Is(err, ErrTooManyResults):
+		return errors.FailedPrecondition
+	case xerrors.Is(err, ErrVersionConflict):
+		return errors.Aborted
+	case xerrors.Is(err, context.Canceled):
+		return errors.Canceled
+	case xerrors.Is(err, context.DeadlineExceeded):
+		return errors.DeadlineExceeded
+	case xerrors.As(err, &netErr) && netErr.Timeout():
+		return errors.DeadlineExceeded
+	case netErr != nil, xerrors.Is(err, driver.ErrBadConn), xerrors.Is(err, io.EOF), xerrors.Is(err, io.ErrUnexpectedEOF):
+		return errors.Unavailable
+	}
+
+	if classify != nil {
+		return classify(err)
+	}
+
+	return errors.Unknown
+}
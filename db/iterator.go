@@ -0,0 +1,24 @@
+package db
+
+import "context"
+
+// Iterator walks a result set one record at a time, without loading it in
+// memory. It must be closed after use.
+type Iterator interface {
+	// Next prepares the next record, returns false when the result set is
+	// exhausted or on error.
+	Next() bool
+	// Scan decodes the current record into dest.
+	Scan(dest interface{}) error
+	// Err returns the error raised during iteration, if any.
+	Err() error
+	// Close releases the underlying cursor.
+	Close() error
+}
+
+// Iterable describes adapters supporting streaming iteration.
+type Iterable interface {
+	// Iterate returns an iterator over records matching the given filter,
+	// using optional sort parameters.
+	Iterate(ctx context.Context, filter interface{}, sortParams *SortParameters) (Iterator, error)
+}
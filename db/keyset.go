@@ -0,0 +1,40 @@
+package db
+
+import (
+	"go.zenithar.org/pkg/errors"
+	"go.zenithar.org/pkg/types"
+)
+
+// KeysetCriterion returns the criterion matching elements located strictly
+// after the given sort key values, according to field directions. Sort fields
+// must not be nullable, null values can't be compared.
+func KeysetCriterion(fields []SortField, after []interface{}) (*Criterion, error) {
+	// Check arguments
+	if len(fields) != len(after) {
+		return nil, errors.Newf(errors.InvalidArgument, nil, "keyset: cursor doesn't match sort parameters")
+	}
+	for i, v := range after {
+		if types.IsNil(v) {
+			return nil, errors.Newf(errors.InvalidArgument, nil, "keyset: null value for sort field '%s'", fields[i].Field)
+		}
+	}
+
+	// (a > x) OR (a = x AND b > y) OR ...
+	alternatives := make([]*Criterion, 0, len(fields))
+	for i, f := range fields {
+		conds := make([]*Criterion, 0, i+1)
+		for j := 0; j < i; j++ {
+			conds = append(conds, Eq(fields[j].Field, after[j]))
+		}
+
+		if f.Direction == Descending {
+			conds = append(conds, Lt(f.Field, after[i]))
+		} else {
+			conds = append(conds, Gt(f.Field, after[i]))
+		}
+
+		alternatives = append(alternatives, And(conds...))
+	}
+
+	return Or(alternatives...), nil
+}
@@ -0,0 +1,63 @@
+package migrations
+
+import (
+	"context"
+	"time"
+)
+
+// Direction is the enumeration for migration directions
+type Direction int
+
+const (
+	// Up applies the migration
+	Up Direction = iota + 1
+	// Down reverts the migration
+	Down
+)
+
+var directions = [...]string{
+	"up",
+	"down",
+}
+
+func (d Direction) String() string {
+	if d < Up || int(d) > len(directions) {
+		return "unknown"
+	}
+	return directions[d-1]
+}
+
+// -----------------------------------------------------------------------------
+
+// Func is a migration step executed with the driver native session.
+type Func func(ctx context.Context, session interface{}) error
+
+// Migration describes a versioned schema change.
+type Migration struct {
+	Version     uint64
+	Description string
+	Up          Func
+	Down        Func
+}
+
+// Record describes an applied migration.
+type Record struct {
+	Version     uint64
+	Description string
+	AppliedAt   time.Time
+}
+
+// Driver is implemented by database adapters to persist applied migrations.
+type Driver interface {
+	// Lock acquires the exclusive migration lock, and initializes the version
+	// table if needed.
+	Lock(ctx context.Context) error
+	// Unlock releases the migration lock.
+	Unlock(ctx context.Context) error
+	// Applied returns applied migrations ordered by version, without requiring
+	// the lock. No migration is applied if the version table doesn't exist.
+	Applied(ctx context.Context) ([]Record, error)
+	// Apply executes the migration step in the given direction and updates
+	// the version table accordingly.
+	Apply(ctx context.Context, m *Migration, direction Direction) error
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"go.zenithar.org/pkg/db/migrations"
+	"go.zenithar.org/pkg/log"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var (
+	migrateUpTargetFlag   uint64
+	migrateDownStepsFlag  int
+	migrateCreateDirFlag  string
+	invalidNameCharRegexp = regexp.MustCompile(`\W+`)
+)
+
+// MigratorFactory builds the migrator used by migration commands
+type MigratorFactory func(ctx context.Context) (*migrations.Migrator, error)
+
+// NewMigrateCommand initialize a cobra migrate command tree, dir is the default
+// directory used to create SQL migration files.
+func NewMigrateCommand(factory MigratorFactory, dir string) *cobra.Command {
+	// migrate
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Manage Database Schema Migrations",
+	}
+
+	// migrate up
+	migrateUpCmd := &cobra.Command{
+		Use:   "up",
+		Short: "Apply pending migrations",
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := commandContext(cmd)
+
+			count, err := build(ctx, factory).Up(ctx, migrateUpTargetFlag)
+			if err != nil {
+				log.For(ctx).Fatal("Error during migrations", zap.Error(err))
+			}
+
+			fmt.Printf("%d migration(s) applied\n", count)
+		},
+	}
+	migrateUpCmd.Flags().Uint64Var(&migrateUpTargetFlag, "target", 0, "Target version (latest if 0)")
+	migrateCmd.AddCommand(migrateUpCmd)
+
+	// migrate down
+	migrateDownCmd := &cobra.Command{
+		Use:   "down",
+		Short: "Revert applied migrations",
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := commandContext(cmd)
+
+			count, err := build(ctx, factory).Down(ctx, migrateDownStepsFlag)
+			if err != nil {
+				log.For(ctx).Fatal("Error during migrations", zap.Error(err))
+			}
+
+			fmt.Printf("%d migration(s) reverted\n", count)
+		},
+	}
+	migrateDownCmd.Flags().IntVar(&migrateDownStepsFlag, "steps", 1, "Count of migrations to revert")
+	migrateCmd.AddCommand(migrateDownCmd)
+
+	// migrate status
+	migrateStatusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Display migration states",
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := commandContext(cmd)
+
+			states, err := build(ctx, factory).Status(ctx)
+			if err != nil {
+				log.For(ctx).Fatal("Error during migration status retrieval", zap.Error(err))
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "VERSION\tDESCRIPTION\tAPPLIED AT")
+			for _, s := range states {
+				appliedAt := "pending"
+				if s.Applied {
+					appliedAt = s.AppliedAt.Format(time.RFC3339)
+				}
+				fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Description, appliedAt)
+			}
+			if err := w.Flush(); err != nil {
+				log.For(ctx).Fatal("Unable to display migration status", zap.Error(err))
+			}
+		},
+	}
+	migrateCmd.AddCommand(migrateStatusCmd)
+
+	// migrate create
+	migrateCreateCmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create SQL migration files",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx := commandContext(cmd)
+
+			name := strings.Trim(invalidNameCharRegexp.ReplaceAllString(strings.ToLower(args[0]), "_"), "_")
+			version := time.Now().UTC().Format("20060102150405")
+
+			for _, direction := range []migrations.Direction{migrations.Up, migrations.Down} {
+				path := filepath.Join(migrateCreateDirFlag, fmt.Sprintf("%s_%s.%s.sql", version, name, direction))
+				if err := ioutil.WriteFile(path, []byte(fmt.Sprintf("-- %s: %s\n", name, direction)), 0644); err != nil {
+					log.For(ctx).Fatal("Unable to create migration file", zap.Error(err), zap.String("path", path))
+				}
+				fmt.Println(path)
+			}
+		},
+	}
+	migrateCreateCmd.Flags().StringVar(&migrateCreateDirFlag, "dir", dir, "Migration files directory")
+	migrateCmd.AddCommand(migrateCreateCmd)
+
+	// Return base command
+	return migrateCmd
+}
+
+// -----------------------------------------------------------------------------
+
+// commandContext returns the command context, or a background context when the
+// command is not executed with one.
+func commandContext(cmd *cobra.Command) context.Context {
+	if ctx := cmd.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+func build(ctx context.Context, factory MigratorFactory) *migrations.Migrator {
+	m, err := factory(ctx)
+	if err != nil {
+		log.For(ctx).Fatal("Unable to initialize migrator", zap.Error(err))
+	}
+	return m
+}
@@ -0,0 +1,165 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.zenithar.org/pkg/errors"
+	"go.zenithar.org/pkg/log"
+
+	"go.uber.org/zap"
+)
+
+// Status describes a migration state.
+type Status struct {
+	Version     uint64
+	Description string
+	Applied     bool
+	AppliedAt   time.Time
+}
+
+// Migrator applies migrations from a set using a driver.
+type Migrator struct {
+	driver Driver
+	set    *Set
+}
+
+// New returns a migrator instance.
+func New(driver Driver, set *Set) *Migrator {
+	return &Migrator{
+		driver: driver,
+		set:    set,
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// Up applies all pending migrations up to the target version, all pending
+// migrations are applied for a 0 target. It returns applied migration count.
+func (m *Migrator) Up(ctx context.Context, target uint64) (int, error) {
+	count := 0
+
+	err := m.locked(ctx, func(applied map[uint64]Record) error {
+		for _, mig := range m.set.Migrations() {
+			if target > 0 && mig.Version > target {
+				break
+			}
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+
+			if err := m.apply(ctx, mig, Up); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+
+	return count, err
+}
+
+// Down reverts the given count of last applied migrations. It returns reverted
+// migration count.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	count := 0
+
+	err := m.locked(ctx, func(applied map[uint64]Record) error {
+		// Revert from the last applied version
+		versions := make([]uint64, 0, len(applied))
+		for version := range applied {
+			versions = append(versions, version)
+		}
+		sort.Slice(versions, func(i, j int) bool {
+			return versions[i] > versions[j]
+		})
+
+		for _, version := range versions {
+			if count >= steps {
+				break
+			}
+
+			mig, ok := m.set.migrations[version]
+			if !ok {
+				return errors.Newf(errors.NotFound, nil, "migrations: applied version %d is not registered", version)
+			}
+			if mig.Down == nil {
+				return errors.Newf(errors.FailedPrecondition, nil, "migrations: version %d is irreversible", version)
+			}
+
+			if err := m.apply(ctx, mig, Down); err != nil {
+				return err
+			}
+			count++
+		}
+
+		return nil
+	})
+
+	return count, err
+}
+
+// Status returns all known migration states ordered by version.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	records, err := m.driver.Applied(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("migrations: unable to retrieve applied versions: %w", err)
+	}
+
+	applied := map[uint64]Record{}
+	for _, r := range records {
+		applied[r.Version] = r
+	}
+
+	res := []Status{}
+	for _, mig := range m.set.Migrations() {
+		r, ok := applied[mig.Version]
+		res = append(res, Status{
+			Version:     mig.Version,
+			Description: mig.Description,
+			Applied:     ok,
+			AppliedAt:   r.AppliedAt,
+		})
+	}
+
+	return res, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func (m *Migrator) locked(ctx context.Context, fn func(applied map[uint64]Record) error) error {
+	// Acquire lock
+	if err := m.driver.Lock(ctx); err != nil {
+		return fmt.Errorf("migrations: unable to acquire lock: %w", err)
+	}
+	defer func() {
+		if err := m.driver.Unlock(ctx); err != nil {
+			log.For(ctx).Error("Unable to release migration lock", zap.Error(err))
+		}
+	}()
+
+	// Retrieve applied migrations
+	records, err := m.driver.Applied(ctx)
+	if err != nil {
+		return fmt.Errorf("migrations: unable to retrieve applied versions: %w", err)
+	}
+
+	applied := map[uint64]Record{}
+	for _, r := range records {
+		applied[r.Version] = r
+	}
+
+	return fn(applied)
+}
+
+func (m *Migrator) apply(ctx context.Context, mig *Migration, direction Direction) error {
+	log.For(ctx).Info("Applying migration", zap.Uint64("version", mig.Version), zap.String("description", mig.Description), zap.Stringer("direction", direction))
+
+	if err := m.driver.Apply(ctx, mig, direction); err != nil {
+		return fmt.Errorf("migrations: unable to apply version %d (%s): %w", mig.Version, direction, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,248 @@
+package migrations_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/xerrors"
+
+	"go.zenithar.org/pkg/db/migrations"
+	"go.zenithar.org/pkg/errors"
+)
+
+type fakeDriver struct {
+	locked  bool
+	applied map[uint64]migrations.Record
+	journal []string
+}
+
+func (d *fakeDriver) Lock(_ context.Context) error {
+	if d.locked {
+		return fmt.Errorf("already locked")
+	}
+	d.locked = true
+	return nil
+}
+
+func (d *fakeDriver) Unlock(_ context.Context) error {
+	d.locked = false
+	return nil
+}
+
+func (d *fakeDriver) Applied(_ context.Context) ([]migrations.Record, error) {
+	res := []migrations.Record{}
+	for _, r := range d.applied {
+		res = append(res, r)
+	}
+	return res, nil
+}
+
+func (d *fakeDriver) Apply(ctx context.Context, m *migrations.Migration, direction migrations.Direction) error {
+	if direction == migrations.Up {
+		if err := m.Up(ctx, d); err != nil {
+			return err
+		}
+		d.applied[m.Version] = migrations.Record{Version: m.Version, Description: m.Description, AppliedAt: time.Now()}
+	} else {
+		if err := m.Down(ctx, d); err != nil {
+			return err
+		}
+		delete(d.applied, m.Version)
+	}
+	return nil
+}
+
+func step(name string) migrations.Func {
+	return func(_ context.Context, session interface{}) error {
+		d := session.(*fakeDriver)
+		d.journal = append(d.journal, name)
+		return nil
+	}
+}
+
+func set(t *testing.T) *migrations.Set {
+	s := migrations.NewSet()
+	if err := s.Register(
+		&migrations.Migration{Version: 2, Description: "add_index", Up: step("up-2"), Down: step("down-2")},
+		&migrations.Migration{Version: 1, Description: "create_users", Up: step("up-1"), Down: step("down-1")},
+		&migrations.Migration{Version: 3, Description: "seed", Up: step("up-3")},
+	); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	return s
+}
+
+func TestMigrator(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		applied     []uint64
+		run         func(*migrations.Migrator) (int, error)
+		wantErr     bool
+		wantCount   int
+		wantJournal []string
+	}{
+		{
+			name:        "up to latest",
+			run:         func(m *migrations.Migrator) (int, error) { return m.Up(context.Background(), 0) },
+			wantCount:   3,
+			wantJournal: []string{"up-1", "up-2", "up-3"},
+		},
+		{
+			name:        "up to target",
+			run:         func(m *migrations.Migrator) (int, error) { return m.Up(context.Background(), 2) },
+			wantCount:   2,
+			wantJournal: []string{"up-1", "up-2"},
+		},
+		{
+			name:        "up pending only",
+			applied:     []uint64{1},
+			run:         func(m *migrations.Migrator) (int, error) { return m.Up(context.Background(), 0) },
+			wantCount:   2,
+			wantJournal: []string{"up-2", "up-3"},
+		},
+		{
+			name:        "down last applied",
+			applied:     []uint64{1, 2},
+			run:         func(m *migrations.Migrator) (int, error) { return m.Down(context.Background(), 2) },
+			wantCount:   2,
+			wantJournal: []string{"down-2", "down-1"},
+		},
+		{
+			name:    "down irreversible",
+			applied: []uint64{1, 2, 3},
+			run:     func(m *migrations.Migrator) (int, error) { return m.Down(context.Background(), 1) },
+			wantErr: true,
+		},
+		{
+			name:    "down unknown version",
+			applied: []uint64{1, 4},
+			run:     func(m *migrations.Migrator) (int, error) { return m.Down(context.Background(), 1) },
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Driver
+			driver := &fakeDriver{applied: map[uint64]migrations.Record{}}
+			for _, v := range tt.applied {
+				driver.applied[v] = migrations.Record{Version: v}
+			}
+
+			// Call operation
+			count, err := tt.run(migrations.New(driver, set(t)))
+			if tt.wantErr && err == nil {
+				t.Fatalf("expected error mst be raised")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("error must not be raised, got %v", err)
+			}
+			if driver.locked {
+				t.Fatalf("lock must be released")
+			}
+			if count != tt.wantCount {
+				t.Fatalf("got count %d, wanted %d", count, tt.wantCount)
+			}
+			if !cmp.Equal(driver.journal, tt.wantJournal) {
+				t.Fatalf("got %v, wanted %v", driver.journal, tt.wantJournal)
+			}
+		})
+	}
+}
+
+func TestMigrator_Status(t *testing.T) {
+	driver := &fakeDriver{applied: map[uint64]migrations.Record{
+		1: {Version: 1},
+	}}
+
+	states, err := migrations.New(driver, set(t)).Status(context.Background())
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+
+	want := []migrations.Status{
+		{Version: 1, Description: "create_users", Applied: true},
+		{Version: 2, Description: "add_index"},
+		{Version: 3, Description: "seed"},
+	}
+	if !cmp.Equal(states, want) {
+		t.Fatalf("got %v, wanted %v", states, want)
+	}
+}
+
+func TestSet_Load(t *testing.T) {
+	dir, err := ioutil.TempDir("", "migrations")
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	for name, content := range map[string]string{
+		"0001_create_users.up.sql":   "CREATE TABLE users (id TEXT);",
+		"0001_create_users.down.sql": "DROP TABLE users;",
+		"0002_add_index.up.sql":      "CREATE INDEX users_idx ON users (id);",
+		"README.md":                  "ignored",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("error must not be raised, got %v", err)
+		}
+	}
+
+	underTest := migrations.NewSet()
+	if err := underTest.Load(http.Dir(dir), "/"); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+
+	loaded := underTest.Migrations()
+	if len(loaded) != 2 {
+		t.Fatalf("got %d migrations, wanted 2", len(loaded))
+	}
+	if loaded[0].Version != 1 || loaded[0].Description != "create_users" || loaded[0].Down == nil {
+		t.Fatalf("unexpected migration %+v", loaded[0])
+	}
+	if loaded[1].Version != 2 || loaded[1].Down != nil {
+		t.Fatalf("unexpected migration %+v", loaded[1])
+	}
+
+	// Duplicate registration
+	if err := underTest.Load(http.Dir(dir), "/"); err == nil {
+		t.Fatalf("expected error mst be raised")
+	}
+
+	// Duplicate version files
+	if err := ioutil.WriteFile(filepath.Join(dir, "1_create_accounts.up.sql"), []byte("CREATE TABLE accounts (id TEXT);"), 0644); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	var e *errors.Error
+	if err := migrations.NewSet().Load(http.Dir(dir), "/"); !xerrors.As(err, &e) || e.Code != errors.AlreadyExists {
+		t.Fatalf("duplicate version must raise an AlreadyExists error, got %v", err)
+	}
+}
+
+func TestDirection_String(t *testing.T) {
+	testCases := []struct {
+		direction migrations.Direction
+		expected  string
+	}{
+		{migrations.Up, "up"},
+		{migrations.Down, "down"},
+		{migrations.Direction(0), "unknown"},
+		{migrations.Direction(42), "unknown"},
+	}
+	for _, tc := range testCases {
+		if got := tc.direction.String(); got != tc.expected {
+			t.Errorf("got %q, wanted %q", got, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"go.zenithar.org/pkg/errors"
+	"go.zenithar.org/pkg/log"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var fileNameRegexp = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Set holds a collection of migrations.
+type Set struct {
+	migrations map[uint64]*Migration
+}
+
+// NewSet returns an empty migration set.
+func NewSet() *Set {
+	return &Set{
+		migrations: map[uint64]*Migration{},
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// Register the given migrations.
+func (s *Set) Register(migrations ...*Migration) error {
+	for _, m := range migrations {
+		// Check arguments
+		if m == nil {
+			return errors.Newf(errors.InvalidArgument, nil, "migrations: migration must not be nil")
+		}
+		if m.Version == 0 {
+			return errors.Newf(errors.InvalidArgument, nil, "migrations: version must be greater than 0")
+		}
+		if m.Up == nil {
+			return errors.Newf(errors.InvalidArgument, nil, "migrations: version %d has no up step", m.Version)
+		}
+		if _, ok := s.migrations[m.Version]; ok {
+			return errors.Newf(errors.AlreadyExists, nil, "migrations: version %d already registered", m.Version)
+		}
+
+		s.migrations[m.Version] = m
+	}
+
+	return nil
+}
+
+// Load registers SQL migrations from the given directory of the filesystem.
+// Files must be named as `<version>_<description>.(up|down).sql`.
+func (s *Set) Load(fs http.FileSystem, dir string) error {
+	// Open directory
+	f, err := fs.Open(dir)
+	if err != nil {
+		return fmt.Errorf("migrations: unable to open directory '%s': %w", dir, err)
+	}
+	defer log.SafeClose(f, "Unable to close directory")
+
+	infos, err := f.Readdir(-1)
+	if err != nil {
+		return fmt.Errorf("migrations: unable to list directory '%s': %w", dir, err)
+	}
+
+	loaded := map[uint64]*Migration{}
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+
+		parts := fileNameRegexp.FindStringSubmatch(info.Name())
+		if parts == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return errors.Newf(errors.InvalidArgument, err, "migrations: invalid version for file '%s'", info.Name())
+		}
+
+		// Read statements
+		query, err := readFile(fs, path.Join(dir, info.Name()))
+		if err != nil {
+			return err
+		}
+
+		m, ok := loaded[version]
+		if !ok {
+			m = &Migration{Version: version, Description: parts[2]}
+			loaded[version] = m
+		}
+
+		// Each version must be defined by one up and one down file at most
+		step := &m.Up
+		if parts[3] == "down" {
+			step = &m.Down
+		}
+		if m.Description != parts[2] || *step != nil {
+			return errors.Newf(errors.AlreadyExists, nil, "migrations: version %d is defined by several files, '%s' is a duplicate", version, info.Name())
+		}
+		*step = SQL(query)
+	}
+
+	// Register loaded migrations
+	for _, m := range loaded {
+		if err := s.Register(m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Migrations returns registered migrations ordered by version.
+func (s *Set) Migrations() []*Migration {
+	res := make([]*Migration, 0, len(s.migrations))
+	for _, m := range s.migrations {
+		res = append(res, m)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Version < res[j].Version
+	})
+	return res
+}
+
+// -----------------------------------------------------------------------------
+
+// SQL returns a migration step executing the given statements, the session
+// must be a sql executor.
+func SQL(query string) Func {
+	return func(ctx context.Context, session interface{}) error {
+		exec, ok := session.(sqlx.ExecerContext)
+		if !ok {
+			return errors.Newf(errors.FailedPrecondition, nil, "migrations: session '%T' is not a sql executor", session)
+		}
+
+		if _, err := exec.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("migrations: unable to execute statements: %w", err)
+		}
+
+		return nil
+	}
+}
+
+func readFile(fs http.FileSystem, name string) (string, error) {
+	f, err := fs.Open(name)
+	if err != nil {
+		return "", fmt.Errorf("migrations: unable to open file '%s': %w", name, err)
+	}
+	defer log.SafeClose(f, "Unable to close file")
+
+	content, err := ioutil.ReadAll(f)
+	if err != nil {
+		return "", fmt.Errorf("migrations: unable to read file '%s': %w", name, err)
+	}
+
+	return string(content), nil
+}
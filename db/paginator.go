@@ -2,6 +2,9 @@ package db
 
 import (
 	"math"
+
+	"go.zenithar.org/pkg/errors"
+	"go.zenithar.org/pkg/types"
 )
 
 const (
@@ -14,6 +17,13 @@ type Pagination struct {
 	Page    uint
 	PerPage uint
 	total   uint
+
+	// Keyset pagination
+	codec      *CursorCodec
+	scope      string
+	after      []interface{}
+	hasNext    bool
+	nextCursor string
 }
 
 // SetTotal is used to defines the total count of paginated values.
@@ -58,6 +68,9 @@ func (p *Pagination) NextPage() uint {
 
 // HasNext returns the status if current page has a next one
 func (p *Pagination) HasNext() bool {
+	if p.IsKeyset() {
+		return p.hasNext
+	}
 	return p.Page+1 <= p.NumPages()
 }
 
@@ -89,6 +102,78 @@ func NewPaginator(page, perPage uint) *Pagination {
 	}
 }
 
+// NewCursorPaginator returns a keyset pagination holder, starting after the
+// position encoded in the given cursor, or from the beginning for a blank one.
+func NewCursorPaginator(codec *CursorCodec, cursor string, perPage uint) (*Pagination, error) {
+	p := NewPaginator(1, perPage)
+	p.codec = codec
+
+	// Decode cursor
+	if cursor != "" {
+		scope, after, err := codec.Decode(cursor)
+		if err != nil {
+			return nil, err
+		}
+		p.scope, p.after = scope, after
+	}
+
+	// Return paginator instance
+	return p, nil
+}
+
+// IsKeyset returns true for cursor based pagination
+func (p *Pagination) IsKeyset() bool {
+	return p.codec != nil
+}
+
+// After returns the sort key values of the last element of the previous page
+func (p *Pagination) After() []interface{} {
+	return p.after
+}
+
+// Bind the pagination to the given keyset query, the cursor is rejected if it
+// has been issued for another table, filter or sort parameters.
+func (p *Pagination) Bind(table string, filter interface{}, fields []SortField) error {
+	scope := CursorScope(table, filter, fields)
+	if len(p.after) > 0 && p.scope != scope {
+		return errors.Newf(errors.InvalidArgument, nil, "cursor: doesn't match query parameters")
+	}
+	p.scope = scope
+
+	return nil
+}
+
+// SetNext is used to defines the sort key values of the last element of the
+// current page, and if there is a next page. Sort key values must not be null.
+func (p *Pagination) SetNext(values []interface{}, hasNext bool) error {
+	p.hasNext = hasNext
+	p.nextCursor = ""
+
+	if !hasNext {
+		return nil
+	}
+
+	// Null values can't be compared for keyset pagination
+	for i, v := range values {
+		if types.IsNil(v) {
+			return errors.Newf(errors.FailedPrecondition, nil, "cursor: sort key value at position %d must not be null", i)
+		}
+	}
+
+	cursor, err := p.codec.Encode(p.scope, values)
+	if err != nil {
+		return err
+	}
+	p.nextCursor = cursor
+
+	return nil
+}
+
+// NextCursor returns the cursor of the next page, blank if there is no next page
+func (p *Pagination) NextCursor() string {
+	return p.nextCursor
+}
+
 // -----------------------------------------------------------------------------
 
 func minuint(a, b uint) uint {
@@ -0,0 +1,25 @@
+package db
+
+import "context"
+
+// Repository describes the CRUD contract implemented by all database adapters.
+type Repository interface {
+	// Create a record.
+	Create(ctx context.Context, data interface{}) error
+	// WhereCount returns the record count matching the given filter.
+	WhereCount(ctx context.Context, filter interface{}) (int, error)
+	// WhereAndFetchOne decodes the first record matching the given filter
+	// into result, returns ErrNoResult if none matches.
+	WhereAndFetchOne(ctx context.Context, filter interface{}, result interface{}) error
+	// Update records matching the given filter with the updates set, returns
+	// ErrNoModification if none matches.
+	Update(ctx context.Context, updates map[string]interface{}, filter interface{}) error
+	// RemoveOne removes a record matching the given filter, returns
+	// ErrNoModification if none matches.
+	RemoveOne(ctx context.Context, filter interface{}) error
+	// Search records matching the given filter, decoded into results, using
+	// optional pagination and sort parameters. It returns the total count of
+	// matching records, or ErrNoResult if none matches. The total count is not
+	// computed for keyset pagination, the page record count is returned.
+	Search(ctx context.Context, filter interface{}, pagination *Pagination, sortParams *SortParameters, results interface{}) (int, error)
+}
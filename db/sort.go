@@ -1,6 +1,10 @@
 package db
 
-import "strings"
+import (
+	"strings"
+
+	"go.zenithar.org/pkg/errors"
+)
 
 // SortDirection is the enumeration for sort
 type SortDirection int
@@ -23,27 +27,120 @@ func (m SortDirection) String() string {
 
 // -----------------------------------------------------------------------------
 
-// SortParameters contains a hashmap of field name with sort direction
-type SortParameters map[string]SortDirection
+// SortField describes a sort key with its direction
+type SortField struct {
+	Field     string
+	Direction SortDirection
+}
+
+// SortParameters contains an ordered list of sort keys
+type SortParameters []SortField
 
 // SortConverter convert a list of string to a SortParameters instance
 func SortConverter(sorts []string) *SortParameters {
 	params := SortParameters{}
 
-	if len(sorts) > 0 {
-		for _, cond := range sorts {
-			if len(strings.TrimSpace(cond)) > 0 {
-				switch cond[0] {
-				case '-':
-					params[cond[1:]] = Descending
-				case '+', ' ':
-					params[cond[1:]] = Ascending
-				default:
-					params[cond] = Ascending
-				}
-			}
+	for _, cond := range sorts {
+		if f, ok := parseSortField(cond); ok {
+			params = params.set(f)
 		}
 	}
 
 	return &params
 }
+
+// ParseSort parses a comma separated sort specification (`-created,+name`),
+// fields must be part of the allowed ones.
+func ParseSort(spec string, allowed ...string) (*SortParameters, error) {
+	params := SortParameters{}
+
+	for _, cond := range strings.Split(spec, ",") {
+		f, ok := parseSortField(cond)
+		if !ok {
+			continue
+		}
+		if params.Has(f.Field) {
+			return nil, errors.Newf(errors.InvalidArgument, nil, "sort: duplicated field '%s'", f.Field)
+		}
+		params = append(params, f)
+	}
+
+	// Check fields
+	if err := params.Validate(func(field string) bool {
+		for _, a := range allowed {
+			if a == field {
+				return true
+			}
+		}
+		return false
+	}); err != nil {
+		return nil, err
+	}
+
+	return &params, nil
+}
+
+// Has returns true if the given field is part of sort parameters
+func (p SortParameters) Has(field string) bool {
+	for _, f := range p {
+		if f.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that all fields are accepted by the given allow-list function.
+func (p SortParameters) Validate(allowed func(field string) bool) error {
+	for _, f := range p {
+		if f.Field == "" {
+			return errors.Newf(errors.InvalidArgument, nil, "sort: field must not be blank")
+		}
+		if f.Direction != Ascending && f.Direction != Descending {
+			return errors.Newf(errors.InvalidArgument, nil, "sort: invalid direction for field '%s'", f.Field)
+		}
+		if allowed == nil || !allowed(f.Field) {
+			return errors.Newf(errors.InvalidArgument, nil, "sort: field '%s' is not sortable", f.Field)
+		}
+	}
+	return nil
+}
+
+// TieBreak returns a copy of sort parameters with the primary key appended in
+// ascending order, if not already present, to ensure a stable order.
+func (p SortParameters) TieBreak(primaryKey string) SortParameters {
+	res := append(SortParameters{}, p...)
+	if !res.Has(primaryKey) {
+		res = append(res, SortField{Field: primaryKey, Direction: Ascending})
+	}
+	return res
+}
+
+// -----------------------------------------------------------------------------
+
+func (p SortParameters) set(f SortField) SortParameters {
+	for i := range p {
+		if p[i].Field == f.Field {
+			p[i].Direction = f.Direction
+			return p
+		}
+	}
+	return append(p, f)
+}
+
+func parseSortField(cond string) (SortField, bool) {
+	// A '+' decoded as a space from query string is trimmed too
+	cond = strings.TrimSpace(cond)
+	if len(cond) == 0 {
+		return SortField{}, false
+	}
+
+	switch cond[0] {
+	case '-':
+		return SortField{Field: strings.TrimSpace(cond[1:]), Direction: Descending}, true
+	case '+':
+		return SortField{Field: strings.TrimSpace(cond[1:]), Direction: Ascending}, true
+	default:
+		return SortField{Field: cond, Direction: Ascending}, true
+	}
+}
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSortConverter(t *testing.T) {
+	Convey("Given a list of sort conditions", t, func() {
+		sorts := []string{"-created", "+name", "", "age", "-name"}
+
+		Convey("When converting them", func() {
+			params := SortConverter(sorts)
+
+			Convey("Then the order should be preserved", func() {
+				So(*params, ShouldResemble, SortParameters{
+					{Field: "created", Direction: Descending},
+					{Field: "name", Direction: Descending},
+					{Field: "age", Direction: Ascending},
+				})
+			})
+		})
+	})
+}
+
+func TestParseSort(t *testing.T) {
+	allowed := []string{"created", "name"}
+
+	Convey("Given a sort specification", t, func() {
+
+		Convey("When all fields are allowed", func() {
+			params, err := ParseSort("-created, name", allowed...)
+
+			Convey("Then the sort parameters should be ordered", func() {
+				So(err, ShouldBeNil)
+				So(*params, ShouldResemble, SortParameters{
+					{Field: "created", Direction: Descending},
+					{Field: "name", Direction: Ascending},
+				})
+			})
+
+			Convey("Then the primary key should be used as tie-breaker", func() {
+				So(params.TieBreak("id"), ShouldResemble, SortParameters{
+					{Field: "created", Direction: Descending},
+					{Field: "name", Direction: Ascending},
+					{Field: "id", Direction: Ascending},
+				})
+			})
+		})
+
+		Convey("When the query string decoded '+' as a space", func() {
+			params, err := ParseSort(" name,-created", allowed...)
+
+			Convey("Then the field should be sorted ascending", func() {
+				So(err, ShouldBeNil)
+				So(*params, ShouldResemble, SortParameters{
+					{Field: "name", Direction: Ascending},
+					{Field: "created", Direction: Descending},
+				})
+			})
+		})
+
+		Convey("When fields are separated by spaces after commas", func() {
+			params, err := ParseSort("-created, +name", allowed...)
+
+			Convey("Then the prefixes should be parsed", func() {
+				So(err, ShouldBeNil)
+				So(*params, ShouldResemble, SortParameters{
+					{Field: "created", Direction: Descending},
+					{Field: "name", Direction: Ascending},
+				})
+			})
+		})
+
+		Convey("When the specification is blank", func() {
+			params, err := ParseSort("", allowed...)
+
+			Convey("Then the sort parameters should be empty", func() {
+				So(err, ShouldBeNil)
+				So(*params, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When a field is not allowed", func() {
+			_, err := ParseSort("-created,password", allowed...)
+
+			Convey("Then an error should be raised", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When a field is duplicated", func() {
+			_, err := ParseSort("-created,+created", allowed...)
+
+			Convey("Then an error should be raised", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
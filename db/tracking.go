@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"go.zenithar.org/pkg/types"
+)
+
+// Tracking describes the columns automatically maintained by adapters, blank
+// names are ignored.
+type Tracking struct {
+	CreatedAt string
+	UpdatedAt string
+	DeletedAt string
+	CreatedBy string
+	UpdatedBy string
+}
+
+// SoftDelete returns true if deleted elements are marked instead of removed.
+func (t Tracking) SoftDelete() bool {
+	return t.DeletedAt != ""
+}
+
+// OnCreate returns the values to set when creating an element.
+func (t Tracking) OnCreate(ctx context.Context, now time.Time) map[string]interface{} {
+	res := t.OnUpdate(ctx, now)
+	set(res, t.CreatedAt, now)
+	if actor := types.Actor(ctx); actor != "" {
+		set(res, t.CreatedBy, actor)
+	}
+	return res
+}
+
+// OnUpdate returns the values to set when updating an element.
+func (t Tracking) OnUpdate(ctx context.Context, now time.Time) map[string]interface{} {
+	res := map[string]interface{}{}
+	set(res, t.UpdatedAt, now)
+	if actor := types.Actor(ctx); actor != "" {
+		set(res, t.UpdatedBy, actor)
+	}
+	return res
+}
+
+// OnDelete returns the values to set when marking an element as deleted.
+func (t Tracking) OnDelete(ctx context.Context, now time.Time) map[string]interface{} {
+	res := t.OnUpdate(ctx, now)
+	set(res, t.DeletedAt, now)
+	return res
+}
+
+// -----------------------------------------------------------------------------
+
+func set(values map[string]interface{}, column string, value interface{}) {
+	if column != "" {
+		values[column] = value
+	}
+}
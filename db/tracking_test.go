@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.zenithar.org/pkg/types"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTracking(t *testing.T) {
+	now := time.Date(2020, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	Convey("Given a full tracking definition", t, func() {
+		tracking := Tracking{
+			CreatedAt: "created_at",
+			UpdatedAt: "updated_at",
+			DeletedAt: "deleted_at",
+			CreatedBy: "created_by",
+			UpdatedBy: "updated_by",
+		}
+
+		Convey("When an actor is in the context", func() {
+			ctx := types.WithActor(context.Background(), "alice")
+
+			Convey("Then creation should set timestamps and principal", func() {
+				So(tracking.OnCreate(ctx, now), ShouldResemble, map[string]interface{}{
+					"created_at": now,
+					"updated_at": now,
+					"created_by": "alice",
+					"updated_by": "alice",
+				})
+			})
+
+			Convey("Then deletion should mark the element", func() {
+				So(tracking.SoftDelete(), ShouldBeTrue)
+				So(tracking.OnDelete(ctx, now), ShouldResemble, map[string]interface{}{
+					"updated_at": now,
+					"deleted_at": now,
+					"updated_by": "alice",
+				})
+			})
+		})
+
+		Convey("When no actor is in the context", func() {
+			Convey("Then principal columns should not be set", func() {
+				So(tracking.OnUpdate(context.Background(), now), ShouldResemble, map[string]interface{}{
+					"updated_at": now,
+				})
+			})
+		})
+	})
+
+	Convey("Given an empty tracking definition", t, func() {
+		tracking := Tracking{}
+
+		Convey("Then nothing should be set", func() {
+			So(tracking.SoftDelete(), ShouldBeFalse)
+			So(tracking.OnCreate(types.WithActor(context.Background(), "alice"), now), ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,27 @@
+package db
+
+import "go.zenithar.org/pkg/errors"
+
+// ExtractVersion returns the expected version held by the updates set for the
+// given version field, and a copy of the updates set without it.
+func ExtractVersion(updates map[string]interface{}, field string) (interface{}, map[string]interface{}, error) {
+	expected, ok := updates[field]
+	if !ok || expected == nil {
+		return nil, nil, errors.Newf(errors.InvalidArgument, nil, "version: expected version '%s' must be part of updates", field)
+	}
+
+	res := make(map[string]interface{}, len(updates))
+	for k, v := range updates {
+		if k != field {
+			res[k] = v
+		}
+	}
+
+	return expected, res, nil
+}
+
+// VersionConflict returns the error raised when an update doesn't match the
+// expected version.
+func VersionConflict(table string, expected interface{}) error {
+	return errors.Newf(errors.Aborted, ErrVersionConflict, "version: %s was modified concurrently, expected version '%v'", table, expected)
+}
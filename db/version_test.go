@@ -0,0 +1,48 @@
+package db
+
+import (
+	"testing"
+
+	"go.zenithar.org/pkg/errors"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/xerrors"
+)
+
+func TestExtractVersion(t *testing.T) {
+	Convey("Given an updates set", t, func() {
+		updates := map[string]interface{}{"name": "foo", "version": 3}
+
+		Convey("When extracting the expected version", func() {
+			expected, rest, err := ExtractVersion(updates, "version")
+
+			Convey("Then the version should be removed from a copy of updates", func() {
+				So(err, ShouldBeNil)
+				So(expected, ShouldEqual, 3)
+				So(rest, ShouldResemble, map[string]interface{}{"name": "foo"})
+				So(updates, ShouldContainKey, "version")
+			})
+		})
+
+		Convey("When the expected version is missing", func() {
+			_, _, err := ExtractVersion(updates, "revision")
+
+			Convey("Then an error should be raised", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestVersionConflict(t *testing.T) {
+	Convey("Given a version conflict error", t, func() {
+		err := VersionConflict("users", 3)
+
+		Convey("Then it should be an aborted error wrapping the sentinel", func() {
+			var e *errors.Error
+			So(xerrors.As(err, &e), ShouldBeTrue)
+			So(e.Code, ShouldEqual, errors.Aborted)
+			So(xerrors.Is(err, ErrVersionConflict), ShouldBeTrue)
+		})
+	})
+}
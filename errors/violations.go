@@ -0,0 +1,27 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldViolation describes a single invalid request field.
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// FieldViolations describes all invalid fields of a request.
+type FieldViolations []FieldViolation
+
+func (v FieldViolations) Error() string {
+	msgs := make([]string, 0, len(v))
+	for _, fv := range v {
+		if fv.Field == "" {
+			msgs = append(msgs, fv.Description)
+			continue
+		}
+		msgs = append(msgs, fmt.Sprintf("%s: %s", fv.Field, fv.Description))
+	}
+	return strings.Join(msgs, "; ")
+}
@@ -7,7 +7,6 @@ require (
 	github.com/Masterminds/squirrel v1.2.0
 	github.com/TheZeroSlave/zapsentry v1.3.0
 	github.com/allegro/bigcache v1.2.1
-	github.com/cheekybits/is v0.0.0-20150225183255-68e9c0620927 // indirect
 	github.com/cloudflare/tableflip v1.0.0
 	github.com/dchest/uniuri v0.0.0-20200228104902-7aecb25e1fe5
 	github.com/fatih/structs v1.1.0
@@ -19,7 +18,7 @@ require (
 	github.com/jmoiron/sqlx v1.2.0
 	github.com/json-iterator/go v1.1.9
 	github.com/lib/pq v1.3.0
-	github.com/matryer/try v0.0.0-20161228173917-9ac251b645a2 // indirect
+	github.com/mattn/go-sqlite3 v1.14.6
 	github.com/mcuadros/go-defaults v1.2.0
 	github.com/oklog/run v1.1.0
 	github.com/onsi/gomega v1.9.0
@@ -36,6 +35,5 @@ require (
 	go.uber.org/zap v1.14.1
 	golang.org/x/xerrors v0.0.0-20191204190536-9bdfabe68543
 	google.golang.org/grpc v1.28.1
-	gopkg.in/matryer/try.v1 v1.0.0-20150601225556-312d2599e12e
 	gopkg.in/rethinkdb/rethinkdb-go.v6 v6.2.1
 )
@@ -3,6 +3,8 @@ package log
 import (
 	"context"
 
+	"go.zenithar.org/pkg/types"
+
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -32,6 +34,9 @@ func (b factory) Bg() Logger {
 
 // For returns a context-aware Logger.
 func (b factory) For(ctx context.Context) Logger {
+	if fields := contextFields(ctx); len(fields) > 0 {
+		return &logger{logger: b.logger.With(fields...)}
+	}
 	return b.Bg()
 }
 
@@ -39,3 +44,34 @@ func (b factory) For(ctx context.Context) Logger {
 func (b factory) With(fields ...zapcore.Field) LoggerFactory {
 	return &factory{logger: b.logger.With(fields...)}
 }
+
+// -----------------------------------------------------------------------------
+
+// contextKeys lists request-scoped metadata exported as log fields, values are
+// read without copying the context metadata.
+var contextKeys = []struct {
+	value func(context.Context) string
+	field string
+}{
+	{value: types.CorrelationID, field: "correlationId"},
+	{value: types.CausationID, field: "causationId"},
+	{value: types.MessageID, field: "messageId"},
+	{value: types.Tenant, field: "tenant"},
+	{value: types.Actor, field: "actor"},
+}
+
+// contextFields extracts request-scoped metadata as log fields.
+func contextFields(ctx context.Context) []zapcore.Field {
+	if ctx == nil {
+		return nil
+	}
+
+	var fields []zapcore.Field
+	for _, k := range contextKeys {
+		if v := k.value(ctx); v != "" {
+			fields = append(fields, zap.String(k.field, v))
+		}
+	}
+
+	return fields
+}
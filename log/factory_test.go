@@ -0,0 +1,46 @@
+package log_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"go.zenithar.org/pkg/log"
+	"go.zenithar.org/pkg/types"
+)
+
+func TestFactory_For(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	underTest := log.NewFactory(zap.New(core))
+
+	// Decorate context
+	ctx := types.WithMessageID(context.Background(), "msg-1")
+	ctx = types.WithCorrelationID(ctx, "corr-1")
+	ctx = types.WithCausationID(ctx, "cause-1")
+	ctx = types.WithTenant(ctx, "tenant-1")
+
+	underTest.For(ctx).Info("foo")
+	underTest.For(context.Background()).Info("bar")
+
+	entries := logs.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, wanted 2", len(entries))
+	}
+
+	want := map[string]interface{}{
+		"correlationId": "corr-1",
+		"causationId":   "cause-1",
+		"messageId":     "msg-1",
+		"tenant":        "tenant-1",
+	}
+	if diff := cmp.Diff(want, entries[0].ContextMap()); diff != "" {
+		t.Errorf("%s", diff)
+	}
+	if got := entries[1].ContextMap(); len(got) != 0 {
+		t.Errorf("context-less logger must not have fields, got %v", got)
+	}
+}
@@ -0,0 +1,35 @@
+package actors
+
+import (
+	"context"
+
+	"go.zenithar.org/pkg/db"
+	"go.zenithar.org/pkg/log"
+
+	"github.com/oklog/run"
+	"go.uber.org/zap"
+	"golang.org/x/xerrors"
+)
+
+// ChangeFeed registers a change feed consumer actor.
+func ChangeFeed(name string, feed db.ChangeFeed, resumeToken string, fn db.ChangeHandler) func(context.Context, *run.Group) {
+	return func(ctx context.Context, group *run.Group) {
+		ctx, cancel := context.WithCancel(ctx)
+
+		// Register change feed actor
+		group.Add(
+			func() error {
+				log.For(ctx).Info("Starting change feed consumer", zap.String("name", name))
+				err := feed.Watch(ctx, resumeToken, fn)
+				if xerrors.Is(err, context.Canceled) {
+					return nil
+				}
+				return err
+			},
+			func(e error) {
+				log.For(ctx).Info("Shutting change feed consumer down", zap.String("name", name))
+				cancel()
+			},
+		)
+	}
+}
@@ -12,7 +12,18 @@ import (
 )
 
 // Signal register an Signal handle actor
+//
+// Deprecated: actors added to the group copy are never run, use Signals.
 func Signal(ctx context.Context, group run.Group) {
+	Signals()(ctx, &group)
+}
+
+// Signals registers an actor stopping the group on SIGINT or SIGTERM.
+func Signals() func(context.Context, *run.Group) {
+	return signals
+}
+
+func signals(ctx context.Context, group *run.Group) {
 	var (
 		cancelInterrupt = make(chan struct{})
 		ch              = make(chan os.Signal, 2)
@@ -10,5 +10,5 @@ import (
 // Reloader defines socket reloader contract.
 type Reloader interface {
 	Listen(network, address string) (net.Listener, error)
-	SetupGracefulRestart(context.Context, run.Group)
+	SetupGracefulRestart(context.Context, *run.Group)
 }
@@ -38,7 +38,7 @@ func Create(ctx context.Context) Reloader {
 }
 
 // SetupGracefulRestart arms the graceful restart handler.
-func (t *TableflipReloader) SetupGracefulRestart(ctx context.Context, group run.Group) {
+func (t *TableflipReloader) SetupGracefulRestart(ctx context.Context, group *run.Group) {
 	ctx, cancel := context.WithCancel(ctx)
 
 	// Register an actor, i.e. an execute and interrupt func, that
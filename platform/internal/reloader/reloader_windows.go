@@ -27,6 +27,6 @@ func (t *UnsupportedReloader) Listen(network, address string) (net.Listener, err
 }
 
 // SetupGracefulRestart does nothing on Windows.
-func (t *UnsupportedReloader) SetupGracefulRestart(context context.Context, group run.Group) {
+func (t *UnsupportedReloader) SetupGracefulRestart(context context.Context, group *run.Group) {
 	// no-op since it isn't supported
 }
@@ -27,7 +27,13 @@ type Server struct {
 	Network         string
 	Address         string
 	Instrumentation InstrumentationConfig
-	Builder         func(ln net.Listener, group run.Group)
+	// Builder registers component actors on a copy of the run group.
+	//
+	// Deprecated: actors added to the group copy are never run, use Setup.
+	Builder func(ln net.Listener, group run.Group)
+	// Setup registers component actors on the run group, used instead of
+	// Builder when set.
+	Setup func(ln net.Listener, group *run.Group)
 }
 
 // Validate server settings
@@ -84,10 +90,10 @@ func Serve(ctx context.Context, srv Server) error {
 	}
 
 	// Setup signal handler
-	actors.Signal(ctx, group)
+	actors.Signals()(ctx, &group)
 
 	// Register graceful restart handler
-	upg.SetupGracefulRestart(ctx, group)
+	upg.SetupGracefulRestart(ctx, &group)
 
 	// Initialiaze network listener
 	ln, err := upg.Listen(srv.Network, srv.Address)
@@ -96,7 +102,13 @@ func Serve(ctx context.Context, srv Server) error {
 	}
 
 	// Initialize the component
-	srv.Builder(ln, group)
+	switch {
+	case srv.Setup != nil:
+		srv.Setup(ln, &group)
+	case srv.Builder != nil:
+		srv.Builder(ln, group)
+	default:
+	}
 
 	// Run goroutine group
 	return group.Run()
@@ -33,3 +33,17 @@ type Reactor interface {
 	// Register a message type handler
 	RegisterHandler(msg interface{}, fn Handler)
 }
+
+// -----------------------------------------------------------------------------
+
+// Registration describes a registered message handler.
+type Registration struct {
+	MessageType string
+	HandlerName string
+}
+
+// Introspector is implemented by reactors exposing their registered handlers.
+type Introspector interface {
+	// Registrations returns registered handlers sorted by message type.
+	Registrations() []Registration
+}
@@ -0,0 +1,206 @@
+package bus
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"go.zenithar.org/pkg/errors"
+	"go.zenithar.org/pkg/log"
+	"go.zenithar.org/pkg/reactor"
+	"go.zenithar.org/pkg/reactor/chain"
+	"go.zenithar.org/pkg/reactor/middlewares"
+	"go.zenithar.org/pkg/types"
+
+	"go.uber.org/zap"
+)
+
+var (
+	_ reactor.Reactor = (*CommandBus)(nil)
+	_ reactor.Reactor = (*QueryBus)(nil)
+)
+
+type bus struct {
+	name     string
+	kind     string
+	chain    chain.Chain
+	delegate reactor.Reactor
+
+	locker     sync.Mutex
+	registered map[reflect.Type]struct{}
+	failures   map[reflect.Type]error
+}
+
+func newBus(name, kind string, opts ...Option) *bus {
+	// Default options
+	dopts := &options{
+		chain: chain.New(),
+	}
+	for _, o := range opts {
+		o(dopts)
+	}
+
+	return &bus{
+		name:       name,
+		kind:       kind,
+		chain:      dopts.chain,
+		delegate:   reactor.New(name),
+		registered: map[reflect.Type]struct{}{},
+		failures:   map[reflect.Type]error{},
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func (b *bus) register(msg interface{}, h reactor.Handler, reg *registration) error {
+	// Check arguments
+	if types.IsNil(msg) {
+		return errors.Newf(errors.InvalidArgument, nil, "%s(%s): message must not be nil", b.kind, b.name)
+	}
+	if types.IsNil(h) {
+		return errors.Newf(errors.InvalidArgument, nil, "%s(%s): handler must not be nil", b.kind, b.name)
+	}
+
+	b.locker.Lock()
+	defer b.locker.Unlock()
+
+	// Only one handler per message type
+	t := reflect.TypeOf(msg)
+	if _, ok := b.registered[t]; ok {
+		return errors.Newf(errors.AlreadyExists, nil, "%s(%s): handler already registered for message type (%T)", b.kind, b.name, msg)
+	}
+
+	// Build handler chain, bus middlewares first
+	wrapped := b.chain.Then(reg.chain.Then(h))
+
+	// Register handler
+	b.delegate.RegisterHandler(msg, reactor.Named(reactor.HandlerName(h), wrapped))
+	b.registered[t] = struct{}{}
+
+	return nil
+}
+
+// fail keeps the error raised by a registration without return value, it is
+// returned when dispatching messages of the given type.
+func (b *bus) fail(msg interface{}, err error) {
+	if err == nil {
+		return
+	}
+
+	log.Bg().Error("Unable to register handler", zap.String("bus", b.name), zap.String("type", fmt.Sprintf("%T", msg)), zap.Error(err))
+
+	b.locker.Lock()
+	b.failures[reflect.TypeOf(msg)] = err
+	b.locker.Unlock()
+}
+
+func (b *bus) failure(req interface{}) error {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+
+	return b.failures[reflect.TypeOf(req)]
+}
+
+// -----------------------------------------------------------------------------
+
+func (b *bus) Send(ctx context.Context, req interface{}, cb reactor.Callback) error {
+	if err := b.failure(req); err != nil {
+		return err
+	}
+	return b.delegate.Send(ctx, req, cb)
+}
+
+func (b *bus) Do(ctx context.Context, req interface{}) (interface{}, error) {
+	if err := b.failure(req); err != nil {
+		return nil, err
+	}
+	return b.delegate.Do(ctx, req)
+}
+
+func (b *bus) Registrations() []reactor.Registration {
+	if i, ok := b.delegate.(reactor.Introspector); ok {
+		return i.Registrations()
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+// CommandBus dispatches commands, messages which may mutate the system state,
+// to their single handler.
+type CommandBus struct {
+	*bus
+}
+
+// NewCommandBus returns a command bus instance.
+func NewCommandBus(name string, opts ...Option) *CommandBus {
+	return &CommandBus{
+		bus: newBus(name, "command-bus", opts...),
+	}
+}
+
+// Register a command handler, with optional registration settings.
+func (b *CommandBus) Register(msg interface{}, h reactor.Handler, opts ...RegisterOption) error {
+	reg := newRegistration(opts...)
+
+	// Caching commands is not supported
+	if reg.cache != nil {
+		return errors.Newf(errors.InvalidArgument, nil, "%s(%s): caching is not supported for commands (%T)", b.kind, b.name, msg)
+	}
+
+	return b.register(msg, h, reg)
+}
+
+// RegisterHandler registers a command handler without registration settings,
+// as required by reactor.Reactor. Registration errors, such as an already
+// registered message type, are returned when dispatching the message type.
+func (b *CommandBus) RegisterHandler(msg interface{}, h reactor.Handler) {
+	b.fail(msg, b.Register(msg, h))
+}
+
+// -----------------------------------------------------------------------------
+
+// QueryBus dispatches queries, read-only messages, to their single handler.
+// Query responses could be cached.
+type QueryBus struct {
+	*bus
+}
+
+// NewQueryBus returns a query bus instance.
+func NewQueryBus(name string, opts ...Option) *QueryBus {
+	return &QueryBus{
+		bus: newBus(name, "query-bus", opts...),
+	}
+}
+
+// Register a query handler, with optional registration settings.
+func (b *QueryBus) Register(msg interface{}, h reactor.Handler, opts ...RegisterOption) error {
+	reg := newRegistration(opts...)
+
+	// Cache is the innermost middleware
+	if reg.cache != nil {
+		reg.chain = reg.chain.Append(middlewares.Cache(reg.cache.storage, reg.cache.ttl, reg.cache.result))
+	}
+
+	return b.register(msg, h, reg)
+}
+
+// RegisterHandler registers a query handler without registration settings,
+// as required by reactor.Reactor. Registration errors, such as an already
+// registered message type, are returned when dispatching the message type.
+func (b *QueryBus) RegisterHandler(msg interface{}, h reactor.Handler) {
+	b.fail(msg, b.Register(msg, h))
+}
+
+// -----------------------------------------------------------------------------
+
+func newRegistration(opts ...RegisterOption) *registration {
+	reg := &registration{
+		chain: chain.New(),
+	}
+	for _, o := range opts {
+		o(reg)
+	}
+	return reg
+}
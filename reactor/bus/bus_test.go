@@ -0,0 +1,191 @@
+package bus_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/xerrors"
+
+	"go.zenithar.org/pkg/cache"
+	"go.zenithar.org/pkg/errors"
+	"go.zenithar.org/pkg/reactor"
+	"go.zenithar.org/pkg/reactor/bus"
+	"go.zenithar.org/pkg/reactor/chain"
+	"go.zenithar.org/pkg/types"
+)
+
+type createUser struct{ Name string }
+type getUser struct{ ID string }
+type user struct{ ID, Name string }
+
+type trace struct {
+	sync.Mutex
+	steps []string
+}
+
+func (tr *trace) middleware(name string) chain.Constructor {
+	return func(next reactor.Handler) reactor.Handler {
+		return reactor.HandlerFunc(func(ctx context.Context, req interface{}) (interface{}, error) {
+			tr.Lock()
+			tr.steps = append(tr.steps, name)
+			tr.Unlock()
+			return next.Handle(ctx, req)
+		})
+	}
+}
+
+type memoryCache struct {
+	sync.Mutex
+	values map[string][]byte
+}
+
+func (c *memoryCache) Get(_ context.Context, key string) ([]byte, error) {
+	c.Lock()
+	defer c.Unlock()
+	if v, ok := c.values[key]; ok {
+		return v, nil
+	}
+	return nil, cache.ErrCacheMiss
+}
+
+func (c *memoryCache) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	c.Lock()
+	defer c.Unlock()
+	c.values[key] = value
+	return nil
+}
+
+func (c *memoryCache) Remove(_ context.Context, key string) error {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.values, key)
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+func TestCommandBus_Register(t *testing.T) {
+	ctx := context.Background()
+	tr := &trace{}
+
+	// Bus
+	underTest := bus.NewCommandBus("commands", bus.WithChain(chain.New(tr.middleware("bus"))))
+
+	err := underTest.Register(&createUser{}, reactor.HandlerFunc(func(_ context.Context, req interface{}) (interface{}, error) {
+		return &user{ID: "1", Name: req.(*createUser).Name}, nil
+	}), bus.Use(chain.New(tr.middleware("type"))))
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+
+	// Duplicate registration
+	err = underTest.Register(&createUser{}, reactor.HandlerFunc(func(_ context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}))
+	var e *errors.Error
+	if !xerrors.As(err, &e) || e.Code != errors.AlreadyExists {
+		t.Fatalf("duplicate registration must raise an AlreadyExists error, got %v", err)
+	}
+
+	// Duplicate registration through reactor contract
+	var r reactor.Reactor = bus.NewCommandBus("reactor")
+	for i := 0; i < 2; i++ {
+		r.RegisterHandler(&createUser{}, reactor.HandlerFunc(func(_ context.Context, req interface{}) (interface{}, error) {
+			return nil, nil
+		}))
+	}
+	if _, err = r.Do(ctx, &createUser{Name: "bob"}); !xerrors.As(err, &e) || e.Code != errors.AlreadyExists {
+		t.Fatalf("duplicate registration must raise an AlreadyExists error on dispatch, got %v", err)
+	}
+	if err = r.Send(ctx, &createUser{Name: "bob"}, func(context.Context, interface{}, error) {}); !xerrors.As(err, &e) || e.Code != errors.AlreadyExists {
+		t.Fatalf("duplicate registration must raise an AlreadyExists error on dispatch, got %v", err)
+	}
+
+	// Caching commands
+	err = underTest.Register(&getUser{}, reactor.HandlerFunc(func(_ context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}), bus.Cached(&memoryCache{values: map[string][]byte{}}, time.Minute, &user{}))
+	if !xerrors.As(err, &e) || e.Code != errors.InvalidArgument {
+		t.Fatalf("cached command must raise an InvalidArgument error, got %v", err)
+	}
+
+	// Call operation
+	got, err := underTest.Do(ctx, &createUser{Name: "foo"})
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if want := (&user{ID: "1", Name: "foo"}); !cmp.Equal(got, want) {
+		t.Fatalf("got %v, wanted %v", got, want)
+	}
+	if want := []string{"bus", "type"}; !cmp.Equal(tr.steps, want) {
+		t.Fatalf("got %v, wanted %v", tr.steps, want)
+	}
+}
+
+func TestQueryBus_Cached(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+
+	// Bus
+	underTest := bus.NewQueryBus("queries")
+
+	err := underTest.Register(&getUser{}, reactor.HandlerFunc(func(_ context.Context, req interface{}) (interface{}, error) {
+		calls++
+		return &user{ID: req.(*getUser).ID, Name: "foo"}, nil
+	}), bus.Cached(&memoryCache{values: map[string][]byte{}}, time.Minute, &user{}))
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		got, err := underTest.Do(ctx, &getUser{ID: "1"})
+		if err != nil {
+			t.Fatalf("error must not be raised, got %v", err)
+		}
+		if want := (&user{ID: "1", Name: "foo"}); !cmp.Equal(got, want) {
+			t.Fatalf("got %v, wanted %v", got, want)
+		}
+	}
+
+	if calls != 1 {
+		t.Fatalf("handler must be called once, got %d", calls)
+	}
+}
+
+func TestQueryBus_CachedPerTenant(t *testing.T) {
+	storage := &memoryCache{values: map[string][]byte{}}
+	calls := 0
+
+	// Bus
+	underTest := bus.NewQueryBus("queries")
+
+	err := underTest.Register(&getUser{}, reactor.HandlerFunc(func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls++
+		return &user{ID: req.(*getUser).ID, Name: types.Tenant(ctx)}, nil
+	}), bus.Cached(storage, time.Minute, &user{}))
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		for _, tenant := range []string{"tenant-a", "tenant-b"} {
+			got, err := underTest.Do(types.WithTenant(context.Background(), tenant), &getUser{ID: "1"})
+			if err != nil {
+				t.Fatalf("error must not be raised, got %v", err)
+			}
+			if want := (&user{ID: "1", Name: tenant}); !cmp.Equal(got, want) {
+				t.Fatalf("got %v, wanted %v", got, want)
+			}
+		}
+	}
+
+	if calls != 2 {
+		t.Fatalf("handler must be called once per tenant, got %d", calls)
+	}
+	if len(storage.values) != 2 {
+		t.Fatalf("cache must contain one entry per tenant, got %d", len(storage.values))
+	}
+}
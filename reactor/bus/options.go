@@ -0,0 +1,57 @@
+package bus
+
+import (
+	"time"
+
+	"go.zenithar.org/pkg/cache"
+	"go.zenithar.org/pkg/reactor/chain"
+)
+
+// Option defines bus option builder.
+type Option func(*options)
+
+type options struct {
+	chain chain.Chain
+}
+
+// WithChain sets the middleware chain applied to all handlers of the bus.
+func WithChain(c chain.Chain) Option {
+	return func(opts *options) {
+		opts.chain = c
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// RegisterOption defines handler registration option builder.
+type RegisterOption func(*registration)
+
+type registration struct {
+	chain chain.Chain
+	cache *cacheOptions
+}
+
+type cacheOptions struct {
+	storage cache.Storage
+	ttl     time.Duration
+	result  interface{}
+}
+
+// Use sets the middleware chain applied to the registered handler only.
+func Use(c chain.Chain) RegisterOption {
+	return func(reg *registration) {
+		reg.chain = c
+	}
+}
+
+// Cached enables response caching for the registered query handler, responses
+// are decoded as new instances of the result type.
+func Cached(storage cache.Storage, ttl time.Duration, result interface{}) RegisterOption {
+	return func(reg *registration) {
+		reg.cache = &cacheOptions{
+			storage: storage,
+			ttl:     ttl,
+			result:  result,
+		}
+	}
+}
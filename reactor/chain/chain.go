@@ -14,7 +14,13 @@ type Constructor func(reactor.Handler) reactor.Handler
 // once created, it will always hold
 // the same set of constructors in the same order.
 type Chain struct {
-	constructors []Constructor
+	links []link
+}
+
+// link is a chain element, optionally named.
+type link struct {
+	name        string
+	constructor Constructor
 }
 
 // New creates a new chain,
@@ -22,7 +28,7 @@ type Chain struct {
 // New serves no other function,
 // constructors are only called upon a call to Then().
 func New(constructors ...Constructor) Chain {
-	return Chain{append(([]Constructor)(nil), constructors...)}
+	return Chain{}.Append(constructors...)
 }
 
 // Then chains the middleware and returns the final reactor.Handler.
@@ -42,8 +48,8 @@ func New(constructors ...Constructor) Chain {
 // when a chain is reused in this way.
 // For proper middleware, this should cause no problems.
 func (c Chain) Then(h reactor.Handler) reactor.Handler {
-	for i := range c.constructors {
-		h = c.constructors[len(c.constructors)-1-i](h)
+	for i := range c.links {
+		h = c.links[len(c.links)-1-i].constructor(h)
 	}
 	return h
 }
@@ -70,9 +76,78 @@ func (c Chain) ThenFunc(fn reactor.HandlerFunc) reactor.Handler {
 //     // requests in stdChain go m1 -> m2
 //     // requests in extChain go m1 -> m2 -> m3 -> m4
 func (c Chain) Append(constructors ...Constructor) Chain {
-	newCons := make([]Constructor, 0, len(c.constructors)+len(constructors))
-	newCons = append(newCons, c.constructors...)
-	newCons = append(newCons, constructors...)
+	newLinks := make([]link, 0, len(c.links)+len(constructors))
+	newLinks = append(newLinks, c.links...)
+	for _, cons := range constructors {
+		newLinks = append(newLinks, link{constructor: cons})
+	}
+
+	return Chain{newLinks}
+}
+
+// AppendNamed extends a chain, adding the specified constructors as a named
+// middleware, so that it can be inspected or removed later.
+//
+// AppendNamed returns a new chain, leaving the original one untouched.
+//
+//     stdChain := chain.New(m1).AppendNamed("authz", m2, m3)
+//     stdChain.Names() // ["authz"]
+func (c Chain) AppendNamed(name string, constructors ...Constructor) Chain {
+	group := New(constructors...)
+
+	newLinks := make([]link, 0, len(c.links)+1)
+	newLinks = append(newLinks, c.links...)
+	newLinks = append(newLinks, link{name: name, constructor: group.Then})
+
+	return Chain{newLinks}
+}
+
+// When extends a chain, adding the specified constructors as the last ones,
+// applied only to requests matching the predicate.
+//
+// When returns a new chain, leaving the original one untouched.
+func (c Chain) When(predicate Predicate, constructors ...Constructor) Chain {
+	return c.Append(When(predicate, constructors...))
+}
+
+// ForType extends a chain, adding the specified constructors as the last ones,
+// applied only to requests of the same type as msg.
+//
+// ForType returns a new chain, leaving the original one untouched.
+func (c Chain) ForType(msg interface{}, constructors ...Constructor) Chain {
+	return c.Append(ForType(msg, constructors...))
+}
+
+// Names returns the named middlewares of the chain in the request flow order.
+func (c Chain) Names() []string {
+	var names []string
+	for _, l := range c.links {
+		if l.name != "" {
+			names = append(names, l.name)
+		}
+	}
+	return names
+}
+
+// Has returns true if the chain contains a middleware with the given name.
+func (c Chain) Has(name string) bool {
+	for _, l := range c.links {
+		if l.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Remove returns a new chain without the middlewares with the given name,
+// leaving the original one untouched.
+func (c Chain) Remove(name string) Chain {
+	newLinks := make([]link, 0, len(c.links))
+	for _, l := range c.links {
+		if l.name != name {
+			newLinks = append(newLinks, l)
+		}
+	}
 
-	return Chain{newCons}
+	return Chain{newLinks}
 }
@@ -0,0 +1,124 @@
+package chain_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.zenithar.org/pkg/reactor"
+	"go.zenithar.org/pkg/reactor/chain"
+)
+
+type userCommand struct{}
+type adminCommand struct{}
+
+func tag(name string) chain.Constructor {
+	return func(next reactor.Handler) reactor.Handler {
+		return reactor.HandlerFunc(func(ctx context.Context, req interface{}) (interface{}, error) {
+			res, err := next.Handle(ctx, req)
+			return append([]string{name}, res.([]string)...), err
+		})
+	}
+}
+
+var final = reactor.HandlerFunc(func(_ context.Context, _ interface{}) (interface{}, error) {
+	return []string{}, nil
+})
+
+func TestChain(t *testing.T) {
+	isAdmin := func(_ context.Context, req interface{}) bool {
+		_, ok := req.(*adminCommand)
+		return ok
+	}
+
+	testCases := []struct {
+		name    string
+		chain   chain.Chain
+		request interface{}
+		want    []string
+	}{
+		{
+			name:    "empty",
+			chain:   chain.New(),
+			request: &userCommand{},
+			want:    []string{},
+		},
+		{
+			name:    "ordered",
+			chain:   chain.New(tag("m1"), tag("m2")).Append(tag("m3")),
+			request: &userCommand{},
+			want:    []string{"m1", "m2", "m3"},
+		},
+		{
+			name:    "when not matching",
+			chain:   chain.New(tag("m1")).When(isAdmin, tag("authz")),
+			request: &userCommand{},
+			want:    []string{"m1"},
+		},
+		{
+			name:    "when matching",
+			chain:   chain.New(tag("m1")).When(isAdmin, tag("authz")),
+			request: &adminCommand{},
+			want:    []string{"m1", "authz"},
+		},
+		{
+			name:    "for type not matching",
+			chain:   chain.New().ForType(&adminCommand{}, tag("authz"), tag("audit")),
+			request: &userCommand{},
+			want:    []string{},
+		},
+		{
+			name:    "for type matching",
+			chain:   chain.New().ForType(&adminCommand{}, tag("authz"), tag("audit")),
+			request: &adminCommand{},
+			want:    []string{"authz", "audit"},
+		},
+		{
+			name:    "named",
+			chain:   chain.New(tag("m1")).AppendNamed("authz", chain.ForType(&adminCommand{}, tag("authz"))).Append(tag("m2")),
+			request: &adminCommand{},
+			want:    []string{"m1", "authz", "m2"},
+		},
+		{
+			name:    "named removed",
+			chain:   chain.New(tag("m1")).AppendNamed("authz", chain.ForType(&adminCommand{}, tag("authz"))).Append(tag("m2")).Remove("authz"),
+			request: &adminCommand{},
+			want:    []string{"m1", "m2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.chain.Then(final).Handle(context.Background(), tt.request)
+			if err != nil {
+				t.Fatalf("error must not be raised, got %v", err)
+			}
+			if !cmp.Equal(got, tt.want) {
+				t.Fatalf("got %v, wanted %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChain_Names(t *testing.T) {
+	base := chain.New(tag("m1")).AppendNamed("authz", tag("authz")).AppendNamed("audit", tag("audit"))
+
+	if want := []string{"authz", "audit"}; !cmp.Equal(base.Names(), want) {
+		t.Fatalf("got %v, wanted %v", base.Names(), want)
+	}
+	if !base.Has("audit") {
+		t.Fatalf("chain must contain 'audit' middleware")
+	}
+
+	removed := base.Remove("audit")
+	if removed.Has("audit") {
+		t.Fatalf("chain must not contain 'audit' middleware")
+	}
+	if !base.Has("audit") {
+		t.Fatalf("original chain must be untouched")
+	}
+}
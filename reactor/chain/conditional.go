@@ -0,0 +1,38 @@
+package chain
+
+import (
+	"context"
+	"reflect"
+
+	"go.zenithar.org/pkg/reactor"
+)
+
+// Predicate is used to select requests handled by conditional middlewares.
+type Predicate func(ctx context.Context, req interface{}) bool
+
+// When returns a constructor applying the given constructors only to requests
+// matching the predicate, other requests are passed to the next handler.
+func When(predicate Predicate, constructors ...Constructor) Constructor {
+	group := New(constructors...)
+
+	return func(next reactor.Handler) reactor.Handler {
+		wrapped := group.Then(next)
+
+		return reactor.HandlerFunc(func(ctx context.Context, req interface{}) (interface{}, error) {
+			if predicate(ctx, req) {
+				return wrapped.Handle(ctx, req)
+			}
+			return next.Handle(ctx, req)
+		})
+	}
+}
+
+// ForType returns a constructor applying the given constructors only to
+// requests of the same type as msg.
+func ForType(msg interface{}, constructors ...Constructor) Constructor {
+	t := reflect.TypeOf(msg)
+
+	return When(func(_ context.Context, req interface{}) bool {
+		return reflect.TypeOf(req) == t
+	}, constructors...)
+}
@@ -3,10 +3,13 @@ package reactor
 import (
 	"context"
 	"reflect"
+	"sort"
 	"sync"
 
 	"go.zenithar.org/pkg/errors"
 	"go.zenithar.org/pkg/types"
+
+	"github.com/dchest/uniuri"
 )
 
 type defaultReactor struct {
@@ -38,10 +41,13 @@ func (r *defaultReactor) Send(ctx context.Context, req interface{}, cb Callback)
 		return errors.Newf(errors.Internal, nil, "reactor(%s): unexpected msg type received (%T)", r.name, req)
 	}
 
+	// Propagate metadata
+	hctx := messageContext(ctx)
+
 	// Fork as goroutine
 	go func() {
-		res, err := h.Handle(ctx, req)
-		cb(ctx, res, err)
+		res, err := h.Handle(hctx, req)
+		cb(hctx, res, err)
 	}()
 
 	// No error
@@ -61,7 +67,7 @@ func (r *defaultReactor) Do(ctx context.Context, req interface{}) (interface{},
 	}
 
 	// Delegate to handler
-	return h.Handle(ctx, req)
+	return h.Handle(messageContext(ctx), req)
 }
 
 func (r *defaultReactor) RegisterHandler(msg interface{}, fn Handler) {
@@ -69,3 +75,47 @@ func (r *defaultReactor) RegisterHandler(msg interface{}, fn Handler) {
 	r.handlers[reflect.TypeOf(msg)] = fn
 	r.locker.Unlock()
 }
+
+func (r *defaultReactor) Registrations() []Registration {
+	r.locker.Lock()
+	defer r.locker.Unlock()
+
+	res := make([]Registration, 0, len(r.handlers))
+	for t, h := range r.handlers {
+		res = append(res, Registration{
+			MessageType: t.String(),
+			HandlerName: HandlerName(h),
+		})
+	}
+
+	// Sort by message type
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].MessageType < res[j].MessageType
+	})
+
+	return res
+}
+
+// -----------------------------------------------------------------------------
+
+// messageContext returns a context for message handling, with a new message
+// identifier, the caller message as cause, and the flow correlation identifier.
+func messageContext(ctx context.Context) context.Context {
+	md := types.MetadataFromContext(ctx)
+
+	// Generate message identifier
+	id := uniuri.New()
+
+	// Link to caller message
+	if parent := md.First(types.MetadataMessageID); parent != "" {
+		md.Set(types.MetadataCausationID, parent)
+	}
+
+	// Start a new flow if needed
+	if !md.Has(types.MetadataCorrelationID) {
+		md.Set(types.MetadataCorrelationID, id)
+	}
+	md.Set(types.MetadataMessageID, id)
+
+	return types.WithMetadata(ctx, md)
+}
@@ -3,9 +3,12 @@ package reactor_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"go.zenithar.org/pkg/reactor"
+	"go.zenithar.org/pkg/reactor/reactortest"
+	"go.zenithar.org/pkg/types"
 )
 
 func TestDefaultReactor_Do(t *testing.T) {
@@ -120,19 +123,90 @@ func TestDefaultReactor_Send(t *testing.T) {
 			}
 
 			// Call operation
-			err := underTest.Send(ctx, tt.request, func(_ context.Context, got interface{}, err error) {
-				if !cmp.Equal(got, tt.want) {
-					t.Fatalf("got %v, wanted %v", got, tt.want)
-				}
-				return
-			})
+			cb := reactortest.NewCallback()
+			err := underTest.Send(ctx, tt.request, cb.Func())
 			if tt.wantErr && err == nil {
 				t.Fatalf("expected error mst be raised")
 			}
 			if !tt.wantErr && err != nil {
 				t.Fatalf("error must not be raised, got %v", err)
 			}
+			if err != nil {
+				cb.AssertNotCalled(t, 10*time.Millisecond)
+				return
+			}
 
+			// Check callback
+			res := cb.Wait(t, time.Second)
+			if !cmp.Equal(res.Response, tt.want) {
+				t.Fatalf("got %v, wanted %v", res.Response, tt.want)
+			}
 		})
 	}
 }
+
+func TestDefaultReactor_Registrations(t *testing.T) {
+	type first struct{}
+	type second struct{}
+
+	// Reactor
+	underTest := reactor.New("registrations")
+	underTest.RegisterHandler(&second{}, reactor.Named("second-handler", reactor.HandlerFunc(func(_ context.Context, req interface{}) (interface{}, error) {
+		return req, nil
+	})))
+	underTest.RegisterHandler(&first{}, reactor.Named("first-handler", reactor.HandlerFunc(func(_ context.Context, req interface{}) (interface{}, error) {
+		return req, nil
+	})))
+
+	i, ok := underTest.(reactor.Introspector)
+	if !ok {
+		t.Fatalf("reactor must implement introspection")
+	}
+
+	want := []reactor.Registration{
+		{MessageType: "*reactor_test.first", HandlerName: "first-handler"},
+		{MessageType: "*reactor_test.second", HandlerName: "second-handler"},
+	}
+	if got := i.Registrations(); !cmp.Equal(got, want) {
+		t.Fatalf("got %v, wanted %v", got, want)
+	}
+}
+
+func TestDefaultReactor_Metadata(t *testing.T) {
+	type parent struct{}
+	type child struct{}
+
+	// Default instances
+	ctx := types.WithTenant(context.Background(), "tenant")
+
+	// Reactor
+	underTest := reactor.New("metadata")
+
+	var parentCtx, childCtx context.Context
+	underTest.RegisterHandler(&child{}, reactor.HandlerFunc(func(ctx context.Context, req interface{}) (interface{}, error) {
+		childCtx = ctx
+		return nil, nil
+	}))
+	underTest.RegisterHandler(&parent{}, reactor.HandlerFunc(func(ctx context.Context, req interface{}) (interface{}, error) {
+		parentCtx = ctx
+		return underTest.Do(ctx, &child{})
+	}))
+
+	// Call operation
+	if _, err := underTest.Do(ctx, &parent{}); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+
+	if types.CorrelationID(parentCtx) == "" {
+		t.Fatalf("correlation id must be generated")
+	}
+	if types.CorrelationID(childCtx) != types.CorrelationID(parentCtx) {
+		t.Fatalf("correlation id must be propagated, got %q, wanted %q", types.CorrelationID(childCtx), types.CorrelationID(parentCtx))
+	}
+	if types.CausationID(childCtx) != types.MessageID(parentCtx) {
+		t.Fatalf("causation id must reference parent message, got %q, wanted %q", types.CausationID(childCtx), types.MessageID(parentCtx))
+	}
+	if types.Tenant(childCtx) != "tenant" {
+		t.Fatalf("tenant must be propagated, got %q", types.Tenant(childCtx))
+	}
+}
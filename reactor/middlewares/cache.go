@@ -0,0 +1,104 @@
+package middlewares
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"go.zenithar.org/pkg/cache"
+	"go.zenithar.org/pkg/log"
+	"go.zenithar.org/pkg/reactor"
+	"go.zenithar.org/pkg/reactor/chain"
+	"go.zenithar.org/pkg/types"
+
+	"go.uber.org/zap"
+)
+
+// Cache is a response caching middleware for read-only requests. Responses
+// are serialized as JSON and decoded as new instances of the result type.
+// Cache entries are scoped to the tenant and actor of the request context.
+func Cache(storage cache.Storage, ttl time.Duration, result interface{}) chain.Constructor {
+	resultType := reflect.TypeOf(result)
+
+	return func(fn reactor.Handler) reactor.Handler {
+		return reactor.HandlerFunc(func(ctx context.Context, req interface{}) (interface{}, error) {
+			// Compute cache key
+			key, err := cacheKey(ctx, req)
+			if err != nil {
+				log.For(ctx).Warn("Unable to compute cache key", zap.Error(err))
+				return fn.Handle(ctx, req)
+			}
+
+			// Check cache first
+			if payload, err := storage.Get(ctx, key); err == nil {
+				res, err := decodeResult(resultType, payload)
+				if err == nil {
+					return res, nil
+				}
+				log.For(ctx).Warn("Unable to decode cached response", zap.String("key", key), zap.Error(err))
+			}
+
+			// Delegate to next handler
+			res, err := fn.Handle(ctx, req)
+			if err != nil {
+				return res, err
+			}
+
+			// Store response
+			payload, err := json.Marshal(res)
+			if err != nil {
+				log.For(ctx).Warn("Unable to serialize response for cache", zap.String("key", key), zap.Error(err))
+				return res, nil
+			}
+			log.CheckErrCtx(ctx, "Unable to store response in cache", storage.Set(ctx, key, payload, ttl), zap.String("key", key))
+
+			return res, nil
+		})
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func cacheKey(ctx context.Context, req interface{}) (string, error) {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("unable to serialize request: %w", err)
+	}
+
+	// Scope to request principal, responses could be tenant or actor specific
+	h := sha256.New()
+	for _, v := range []string{types.Tenant(ctx), types.Actor(ctx)} {
+		fmt.Fprintf(h, "%d:%s", len(v), v)
+	}
+	h.Write(payload)
+
+	return fmt.Sprintf("%T:%x", req, h.Sum(nil)), nil
+}
+
+func decodeResult(t reflect.Type, payload []byte) (interface{}, error) {
+	if t == nil {
+		var res interface{}
+		err := json.Unmarshal(payload, &res)
+		return res, err
+	}
+
+	// Allocate a new instance
+	var ptr reflect.Value
+	if t.Kind() == reflect.Ptr {
+		ptr = reflect.New(t.Elem())
+	} else {
+		ptr = reflect.New(t)
+	}
+
+	if err := json.Unmarshal(payload, ptr.Interface()); err != nil {
+		return nil, err
+	}
+
+	if t.Kind() == reflect.Ptr {
+		return ptr.Interface(), nil
+	}
+	return ptr.Elem().Interface(), nil
+}
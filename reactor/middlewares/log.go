@@ -14,7 +14,7 @@ import (
 func Log(lf log.LoggerFactory) chain.Constructor {
 	return func(fn reactor.Handler) reactor.Handler {
 		return reactor.HandlerFunc(func(ctx context.Context, req interface{}) (interface{}, error) {
-			lf.For(ctx).Debug("Handling message ...", zap.Any(req))
+			lf.For(ctx).Debug("Handling message ...", zap.Any("request", req))
 
 			res, err := fn.Handle(ctx, req)
 			if err != nil {
@@ -0,0 +1,66 @@
+package middlewares
+
+import (
+	"context"
+	"sort"
+
+	"go.zenithar.org/pkg/errors"
+	"go.zenithar.org/pkg/reactor"
+	"go.zenithar.org/pkg/reactor/chain"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// Validation is a request validation middleware. Requests implementing
+// validation.Validatable are validated before being handled, validation
+// failures are returned as InvalidArgument errors wrapping
+// errors.FieldViolations.
+func Validation() chain.Constructor {
+	return func(fn reactor.Handler) reactor.Handler {
+		return reactor.HandlerFunc(func(ctx context.Context, req interface{}) (interface{}, error) {
+			if v, ok := req.(validation.Validatable); ok {
+				if err := v.Validate(); err != nil {
+					return nil, validationError(req, err)
+				}
+			}
+
+			// Delegate to next handler
+			return fn.Handle(ctx, req)
+		})
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func validationError(req interface{}, err error) error {
+	// Validation rule failure
+	if ierr, ok := err.(validation.InternalError); ok {
+		return errors.Newf(errors.Internal, ierr.InternalError(), "unable to validate request (%T)", req)
+	}
+
+	// Convert to field violations
+	violations := errors.FieldViolations{}
+	flattenViolations(&violations, "", err)
+	sort.Slice(violations, func(i, j int) bool {
+		return violations[i].Field < violations[j].Field
+	})
+
+	return errors.Newf(errors.InvalidArgument, violations, "invalid request (%T)", req)
+}
+
+func flattenViolations(violations *errors.FieldViolations, prefix string, err error) {
+	if errs, ok := err.(validation.Errors); ok {
+		for field, ferr := range errs {
+			if prefix != "" {
+				field = prefix + "." + field
+			}
+			flattenViolations(violations, field, ferr)
+		}
+		return
+	}
+
+	*violations = append(*violations, errors.FieldViolation{
+		Field:       prefix,
+		Description: err.Error(),
+	})
+}
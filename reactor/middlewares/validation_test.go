@@ -0,0 +1,106 @@
+package middlewares_test
+
+import (
+	"context"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/xerrors"
+
+	"go.zenithar.org/pkg/errors"
+	"go.zenithar.org/pkg/reactor"
+	"go.zenithar.org/pkg/reactor/middlewares"
+)
+
+type address struct {
+	City string
+}
+
+func (a address) Validate() error {
+	return validation.ValidateStruct(&a,
+		validation.Field(&a.City, validation.Required),
+	)
+}
+
+type createUser struct {
+	Name    string
+	Address address
+}
+
+func (r *createUser) Validate() error {
+	return validation.ValidateStruct(r,
+		validation.Field(&r.Name, validation.Required),
+		validation.Field(&r.Address),
+	)
+}
+
+func TestValidation(t *testing.T) {
+
+	testCases := []struct {
+		name           string
+		request        interface{}
+		wantErr        bool
+		wantViolations errors.FieldViolations
+	}{
+		{
+			name:    "not validatable",
+			request: &struct{}{},
+			wantErr: false,
+		},
+		{
+			name:    "valid request",
+			request: &createUser{Name: "foo", Address: address{City: "bar"}},
+			wantErr: false,
+		},
+		{
+			name:    "invalid request",
+			request: &createUser{},
+			wantErr: true,
+			wantViolations: errors.FieldViolations{
+				{Field: "Address.City", Description: "cannot be blank"},
+				{Field: "Name", Description: "cannot be blank"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Default instances
+			ctx := context.Background()
+
+			// Middleware
+			underTest := middlewares.Validation()(reactor.HandlerFunc(func(_ context.Context, req interface{}) (interface{}, error) {
+				return req, nil
+			}))
+
+			// Call operation
+			_, err := underTest.Handle(ctx, tt.request)
+			if tt.wantErr && err == nil {
+				t.Fatalf("expected error mst be raised")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("error must not be raised, got %v", err)
+			}
+			if err == nil {
+				return
+			}
+
+			// Check error details
+			var e *errors.Error
+			if !xerrors.As(err, &e) || e.Code != errors.InvalidArgument {
+				t.Fatalf("error must be an InvalidArgument error, got %v", err)
+			}
+			var violations errors.FieldViolations
+			if !xerrors.As(err, &violations) {
+				t.Fatalf("error must wrap field violations, got %v", err)
+			}
+			if !cmp.Equal(violations, tt.wantViolations) {
+				t.Fatalf("got %v, wanted %v", violations, tt.wantViolations)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package reactor
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime"
+)
+
+type namedHandler struct {
+	name string
+	next Handler
+}
+
+// Named decorates the given handler with a name used for introspection.
+func Named(name string, h Handler) Handler {
+	return &namedHandler{
+		name: name,
+		next: h,
+	}
+}
+
+func (h *namedHandler) Name() string {
+	return h.name
+}
+
+func (h *namedHandler) Handle(ctx context.Context, req interface{}) (interface{}, error) {
+	return h.next.Handle(ctx, req)
+}
+
+// -----------------------------------------------------------------------------
+
+// HandlerName returns a printable name of the given handler.
+func HandlerName(h Handler) string {
+	switch hh := h.(type) {
+	case interface{ Name() string }:
+		return hh.Name()
+	case HandlerFunc:
+		if fn := runtime.FuncForPC(reflect.ValueOf(hh).Pointer()); fn != nil {
+			return fn.Name()
+		}
+	}
+	return fmt.Sprintf("%T", h)
+}
@@ -0,0 +1,22 @@
+package outbox
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// HandlerFunc is called for each pending event during dispatch.
+type HandlerFunc func(ctx context.Context, evt interface{}) error
+
+// Store describes outbox persistence contract.
+type Store interface {
+	// Publish serializes and stores the given events using the given executor,
+	// usually the transaction used for the business write.
+	Publish(ctx context.Context, exec sqlx.ExecerContext, events ...interface{}) error
+	// Dispatch locks a batch of pending events and calls fn for each of them.
+	// Successfully handled events are marked as dispatched, failed ones are
+	// retried after a backoff delay and dead-lettered once the attempt limit is
+	// reached. It returns the count of dispatched events.
+	Dispatch(ctx context.Context, batchSize uint64, fn HandlerFunc) (int, error)
+}
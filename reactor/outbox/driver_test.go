@@ -0,0 +1,141 @@
+package outbox
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func init() {
+	sql.Register("outboxtest", &fakeDriver{})
+}
+
+// statement is a query executed through the fake driver.
+type statement struct {
+	Query string
+	Args  []driver.Value
+	InTx  bool
+}
+
+// recorder records statements executed through the fake driver, queries
+// return the configured rows.
+type recorder struct {
+	sync.Mutex
+	columns    []string
+	rows       [][]driver.Value
+	statements []statement
+	commits    int
+	rollbacks  int
+}
+
+func (r *recorder) Statements() []statement {
+	r.Lock()
+	defer r.Unlock()
+	return append([]statement(nil), r.statements...)
+}
+
+var recorders sync.Map
+
+// fakeDB returns a database handle using the fake driver, queries return the
+// given rows.
+func fakeDB(t *testing.T, columns []string, rows ...[]driver.Value) (*sqlx.DB, *recorder) {
+	rec := &recorder{columns: columns, rows: rows}
+	recorders.Store(t.Name(), rec)
+
+	conn, err := sqlx.Open("outboxtest", t.Name())
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		recorders.Delete(t.Name())
+	})
+
+	return conn, rec
+}
+
+// -----------------------------------------------------------------------------
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	rec, _ := recorders.Load(name)
+	return &fakeConn{rec: rec.(*recorder)}, nil
+}
+
+type fakeConn struct {
+	rec  *recorder
+	inTx bool
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, query: query}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.inTx = true
+	return c, nil
+}
+
+func (c *fakeConn) Commit() error {
+	c.rec.Lock()
+	c.rec.commits++
+	c.rec.Unlock()
+	c.inTx = false
+	return nil
+}
+
+func (c *fakeConn) Rollback() error {
+	c.rec.Lock()
+	c.rec.rollbacks++
+	c.rec.Unlock()
+	c.inTx = false
+	return nil
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) record(args []driver.Value) {
+	s.conn.rec.Lock()
+	s.conn.rec.statements = append(s.conn.rec.statements, statement{Query: s.query, Args: args, InTx: s.conn.inTx})
+	s.conn.rec.Unlock()
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.record(args)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.record(args)
+	return &fakeRows{columns: s.conn.rec.columns, rows: s.conn.rec.rows}, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
@@ -0,0 +1,222 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.zenithar.org/pkg/log"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/dchest/uniuri"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// PostgreSQLSchema returns the table creation statement for the outbox table.
+func PostgreSQLSchema(table string) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %[1]s (
+	id              VARCHAR(64) PRIMARY KEY,
+	event_type      VARCHAR(255) NOT NULL,
+	payload         JSONB NOT NULL,
+	created_at      TIMESTAMP WITH TIME ZONE NOT NULL,
+	dispatched_at   TIMESTAMP WITH TIME ZONE NULL,
+	attempts        INTEGER NOT NULL DEFAULT 0,
+	next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	failed_at       TIMESTAMP WITH TIME ZONE NULL,
+	last_error      TEXT NULL
+);
+CREATE INDEX IF NOT EXISTS %[1]s_pending_idx ON %[1]s (next_attempt_at) WHERE dispatched_at IS NULL AND failed_at IS NULL;`, table)
+}
+
+type record struct {
+	ID        string `db:"id"`
+	EventType string `db:"event_type"`
+	Payload   []byte `db:"payload"`
+	Attempts  int    `db:"attempts"`
+}
+
+type pgStore struct {
+	session  *sqlx.DB
+	table    string
+	registry *Registry
+
+	maxAttempts int
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	now         func() time.Time
+}
+
+// PostgreSQL returns an outbox store backed by the given PostgreSQL table.
+func PostgreSQL(session *sqlx.DB, table string, registry *Registry, opts ...Option) Store {
+	s := &pgStore{
+		session:     session,
+		table:       table,
+		registry:    registry,
+		maxAttempts: 10,
+		minBackoff:  time.Second,
+		maxBackoff:  5 * time.Minute,
+		now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+
+	// Apply options
+	for _, o := range opts {
+		o(s)
+	}
+
+	return s
+}
+
+// -----------------------------------------------------------------------------
+
+// Option defines store option builder.
+type Option func(*pgStore)
+
+// WithMaxAttempts sets the dispatch attempt count after which a failing event
+// is dead-lettered and no longer dispatched, 10 by default.
+func WithMaxAttempts(attempts int) Option {
+	return func(s *pgStore) {
+		s.maxAttempts = attempts
+	}
+}
+
+// WithBackoff sets the delay before retrying a failed event, doubled on each
+// attempt from min up to max, 1s to 5m by default.
+func WithBackoff(min, max time.Duration) Option {
+	return func(s *pgStore) {
+		s.minBackoff = min
+		s.maxBackoff = max
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *pgStore) Publish(ctx context.Context, exec sqlx.ExecerContext, events ...interface{}) error {
+	// Nothing to publish
+	if len(events) == 0 {
+		return nil
+	}
+
+	// Prepare query
+	now := s.now()
+	qb := sq.Insert(s.table).
+		Columns("id", "event_type", "payload", "created_at", "next_attempt_at").
+		PlaceholderFormat(sq.Dollar)
+
+	for _, evt := range events {
+		name, payload, err := s.registry.Marshal(evt)
+		if err != nil {
+			return err
+		}
+		qb = qb.Values(uniuri.NewLen(32), name, payload, now, now)
+	}
+
+	// Build sql query
+	q, args, err := qb.ToSql()
+	if err != nil {
+		return fmt.Errorf("outbox: unable to build query: %w", err)
+	}
+
+	// Do the insert query
+	if _, err := exec.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("outbox: unable to execute query: %w", err)
+	}
+
+	// Return no error
+	return nil
+}
+
+func (s *pgStore) Dispatch(ctx context.Context, batchSize uint64, fn HandlerFunc) (count int, err error) {
+	// Start transaction to keep rows locked during dispatch
+	tx, err := s.session.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("outbox: unable to start transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			log.CheckErrCtx(ctx, "Unable to rollback outbox transaction", tx.Rollback())
+		}
+	}()
+
+	// Prepare query, skip rows not due yet, dead-lettered or locked by another
+	// relay
+	now := s.now()
+	q, args, err := sq.Select("id", "event_type", "payload", "attempts").
+		From(s.table).
+		Where(sq.Eq{"dispatched_at": nil, "failed_at": nil}).
+		Where(sq.LtOrEq{"next_attempt_at": now}).
+		OrderBy("next_attempt_at asc", "created_at asc").
+		Limit(batchSize).
+		Suffix("FOR UPDATE SKIP LOCKED").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("outbox: unable to build query: %w", err)
+	}
+
+	var records []record
+	if err = tx.SelectContext(ctx, &records, q, args...); err != nil && err != sql.ErrNoRows {
+		return 0, fmt.Errorf("outbox: unable to execute query: %w", err)
+	}
+
+	for i := range records {
+		rec := &records[i]
+
+		// Decode and dispatch the event
+		evt, errDecode := s.registry.Unmarshal(rec.EventType, rec.Payload)
+		if errDecode == nil {
+			errDecode = fn(ctx, evt)
+		}
+
+		// Update record state
+		qb := sq.Update(s.table).
+			Where(sq.Eq{"id": rec.ID}).
+			Set("attempts", sq.Expr("attempts + 1")).
+			PlaceholderFormat(sq.Dollar)
+		switch attempts := rec.Attempts + 1; {
+		case errDecode == nil:
+			qb = qb.Set("dispatched_at", now).Set("last_error", nil)
+			count++
+		case attempts >= s.maxAttempts:
+			log.For(ctx).Error("Outbox event dead-lettered", zap.String("id", rec.ID), zap.String("type", rec.EventType), zap.Int("attempts", attempts), zap.Error(errDecode))
+			qb = qb.Set("failed_at", now).Set("last_error", errDecode.Error())
+		default:
+			log.For(ctx).Error("Unable to dispatch outbox event", zap.String("id", rec.ID), zap.String("type", rec.EventType), zap.Int("attempts", attempts), zap.Error(errDecode))
+			qb = qb.Set("next_attempt_at", now.Add(s.backoff(attempts))).Set("last_error", errDecode.Error())
+		}
+
+		q, args, err = qb.ToSql()
+		if err != nil {
+			return 0, fmt.Errorf("outbox: unable to build query: %w", err)
+		}
+		if _, err = tx.ExecContext(ctx, q, args...); err != nil {
+			return 0, fmt.Errorf("outbox: unable to execute query: %w", err)
+		}
+	}
+
+	// Commit state changes
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("outbox: unable to commit transaction: %w", err)
+	}
+
+	// Return no error
+	return count, nil
+}
+
+// -----------------------------------------------------------------------------
+
+// backoff returns the delay before the next attempt of an event which failed
+// the given count of attempts.
+func (s *pgStore) backoff(attempts int) time.Duration {
+	delay := s.minBackoff
+	for i := 1; i < attempts && delay < s.maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > s.maxBackoff {
+		delay = s.maxBackoff
+	}
+	return delay
+}
@@ -0,0 +1,164 @@
+package outbox
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jmoiron/sqlx"
+)
+
+type itemShipped struct {
+	ID string `json:"id"`
+}
+
+func testStore(session *sqlx.DB, opts ...Option) *pgStore {
+	registry := NewRegistry()
+	registry.Register("item.shipped", itemShipped{})
+
+	s := PostgreSQL(session, "outbox", registry, opts...).(*pgStore)
+	s.now = func() time.Time {
+		return time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return s
+}
+
+func TestPostgreSQL_Publish(t *testing.T) {
+	ctx := context.Background()
+	conn, rec := fakeDB(t, nil)
+
+	underTest := testStore(conn)
+
+	// Publish inside the business transaction
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if err := underTest.Publish(ctx, tx, itemShipped{ID: "1"}, itemShipped{ID: "2"}); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+
+	statements := rec.Statements()
+	if len(statements) != 1 {
+		t.Fatalf("got %d statements, wanted 1", len(statements))
+	}
+	got := statements[0]
+	if want := "INSERT INTO outbox (id,event_type,payload,created_at,next_attempt_at) VALUES ($1,$2,$3,$4,$5),($6,$7,$8,$9,$10)"; got.Query != want {
+		t.Errorf("got %q, wanted %q", got.Query, want)
+	}
+	if !got.InTx {
+		t.Errorf("events must be published inside the transaction")
+	}
+	if diff := cmp.Diff([]driver.Value{"item.shipped", []byte(`{"id":"1"}`)}, got.Args[1:3]); diff != "" {
+		t.Errorf("%s", diff)
+	}
+
+	// Unregistered events are rejected
+	if err := underTest.Publish(ctx, conn, &struct{}{}); err == nil {
+		t.Fatalf("expected error mst be raised")
+	}
+}
+
+func TestPostgreSQL_Dispatch(t *testing.T) {
+	ctx := context.Background()
+	conn, rec := fakeDB(t, []string{"id", "event_type", "payload", "attempts"},
+		[]driver.Value{"1", "item.shipped", []byte(`{"id":"ok"}`), int64(0)},
+		[]driver.Value{"2", "item.shipped", []byte(`{"id":"ko"}`), int64(2)},
+		[]driver.Value{"3", "item.shipped", []byte(`{"id":"ko"}`), int64(4)},
+		[]driver.Value{"4", "item.unknown", []byte(`{}`), int64(0)},
+	)
+
+	underTest := testStore(conn, WithMaxAttempts(5), WithBackoff(time.Second, time.Minute))
+	now := underTest.now()
+
+	var handled []interface{}
+	count, err := underTest.Dispatch(ctx, 10, func(ctx context.Context, evt interface{}) error {
+		handled = append(handled, evt)
+		if evt.(itemShipped).ID == "ko" {
+			return fmt.Errorf("foo")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if count != 1 {
+		t.Errorf("got %d dispatched events, wanted 1", count)
+	}
+	if diff := cmp.Diff([]interface{}{itemShipped{ID: "ok"}, itemShipped{ID: "ko"}, itemShipped{ID: "ko"}}, handled); diff != "" {
+		t.Errorf("%s", diff)
+	}
+
+	statements := rec.Statements()
+	if len(statements) != 5 {
+		t.Fatalf("got %d statements, wanted 5", len(statements))
+	}
+
+	// Only due events are selected
+	if want := "SELECT id, event_type, payload, attempts FROM outbox WHERE dispatched_at IS NULL AND failed_at IS NULL AND next_attempt_at <= $1 ORDER BY next_attempt_at asc, created_at asc LIMIT 10 FOR UPDATE SKIP LOCKED"; statements[0].Query != want {
+		t.Errorf("got %q, wanted %q", statements[0].Query, want)
+	}
+
+	// Record states are updated
+	testCases := []struct {
+		name  string
+		query string
+		args  []driver.Value
+	}{
+		{
+			name:  "dispatched",
+			query: "UPDATE outbox SET attempts = attempts + 1, dispatched_at = $1, last_error = $2 WHERE id = $3",
+			args:  []driver.Value{now, nil, "1"},
+		},
+		{
+			name:  "retried with backoff",
+			query: "UPDATE outbox SET attempts = attempts + 1, next_attempt_at = $1, last_error = $2 WHERE id = $3",
+			args:  []driver.Value{now.Add(4 * time.Second), "foo", "2"},
+		},
+		{
+			name:  "dead-lettered",
+			query: "UPDATE outbox SET attempts = attempts + 1, failed_at = $1, last_error = $2 WHERE id = $3",
+			args:  []driver.Value{now, "foo", "3"},
+		},
+		{
+			name:  "undecodable",
+			query: "UPDATE outbox SET attempts = attempts + 1, next_attempt_at = $1, last_error = $2 WHERE id = $3",
+			args:  []driver.Value{now.Add(time.Second), "outbox: unregistered event type name 'item.unknown'", "4"},
+		},
+	}
+	for i, tt := range testCases {
+		got := statements[i+1]
+		if got.Query != tt.query {
+			t.Errorf("%s: got %q, wanted %q", tt.name, got.Query, tt.query)
+		}
+		if diff := cmp.Diff(tt.args, got.Args); diff != "" {
+			t.Errorf("%s: %s", tt.name, diff)
+		}
+		if !got.InTx {
+			t.Errorf("%s: state must be updated inside the transaction", tt.name)
+		}
+	}
+
+	if rec.commits != 1 || rec.rollbacks != 0 {
+		t.Errorf("got %d commits and %d rollbacks, wanted 1 commit", rec.commits, rec.rollbacks)
+	}
+}
+
+func TestPostgreSQL_Backoff(t *testing.T) {
+	underTest := testStore(nil, WithBackoff(time.Second, 10*time.Second))
+
+	var got []string
+	for attempts := 1; attempts <= 6; attempts++ {
+		got = append(got, underTest.backoff(attempts).String())
+	}
+	if want := "1s 2s 4s 8s 10s 10s"; strings.Join(got, " ") != want {
+		t.Fatalf("got %v, wanted %v", got, want)
+	}
+}
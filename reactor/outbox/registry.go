@@ -0,0 +1,89 @@
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"go.zenithar.org/pkg/types"
+)
+
+// Registry holds event type names used for serialization.
+type Registry struct {
+	locker sync.RWMutex
+	names  map[reflect.Type]string
+	types  map[string]reflect.Type
+}
+
+// NewRegistry returns an empty event type registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		names: map[reflect.Type]string{},
+		types: map[string]reflect.Type{},
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// Register an event type with the given name.
+func (r *Registry) Register(name string, evt interface{}) {
+	r.locker.Lock()
+	t := reflect.TypeOf(evt)
+	r.names[t] = name
+	r.types[name] = t
+	r.locker.Unlock()
+}
+
+// Marshal returns the registered name and the serialized event.
+func (r *Registry) Marshal(evt interface{}) (string, []byte, error) {
+	// Check if event is nil
+	if types.IsNil(evt) {
+		return "", nil, fmt.Errorf("outbox: event must not be nil")
+	}
+
+	r.locker.RLock()
+	name, ok := r.names[reflect.TypeOf(evt)]
+	r.locker.RUnlock()
+	if !ok {
+		return "", nil, fmt.Errorf("outbox: unregistered event type (%T)", evt)
+	}
+
+	// Serialize event
+	payload, err := json.Marshal(evt)
+	if err != nil {
+		return "", nil, fmt.Errorf("outbox: unable to serialize event (%T): %w", evt, err)
+	}
+
+	return name, payload, nil
+}
+
+// Unmarshal returns a new event instance of the registered type decoded from
+// payload.
+func (r *Registry) Unmarshal(name string, payload []byte) (interface{}, error) {
+	r.locker.RLock()
+	t, ok := r.types[name]
+	r.locker.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("outbox: unregistered event type name '%s'", name)
+	}
+
+	// Allocate a new instance
+	var ptr reflect.Value
+	if t.Kind() == reflect.Ptr {
+		ptr = reflect.New(t.Elem())
+	} else {
+		ptr = reflect.New(t)
+	}
+
+	// Decode payload
+	if err := json.Unmarshal(payload, ptr.Interface()); err != nil {
+		return nil, fmt.Errorf("outbox: unable to decode event '%s': %w", name, err)
+	}
+
+	if t.Kind() == reflect.Ptr {
+		return ptr.Interface(), nil
+	}
+
+	return ptr.Elem().Interface(), nil
+}
@@ -0,0 +1,87 @@
+package outbox_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.zenithar.org/pkg/reactor/outbox"
+)
+
+type userCreated struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type userDeleted struct {
+	ID string `json:"id"`
+}
+
+func TestRegistry_RoundTrip(t *testing.T) {
+
+	testCases := []struct {
+		name    string
+		event   interface{}
+		wantErr bool
+	}{
+		{
+			name:    "nil event",
+			event:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "unregistered event",
+			event:   &struct{}{},
+			wantErr: true,
+		},
+		{
+			name:  "pointer event",
+			event: &userCreated{ID: "123", Name: "foo"},
+		},
+		{
+			name:  "value event",
+			event: userDeleted{ID: "123"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Registry
+			underTest := outbox.NewRegistry()
+			underTest.Register("user.created", &userCreated{})
+			underTest.Register("user.deleted", userDeleted{})
+
+			// Serialize event
+			name, payload, err := underTest.Marshal(tt.event)
+			if tt.wantErr && err == nil {
+				t.Fatalf("expected error mst be raised")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("error must not be raised, got %v", err)
+			}
+			if err != nil {
+				return
+			}
+
+			// Decode event
+			got, err := underTest.Unmarshal(name, payload)
+			if err != nil {
+				t.Fatalf("error must not be raised, got %v", err)
+			}
+			if !cmp.Equal(got, tt.event) {
+				t.Fatalf("got %v, wanted %v", got, tt.event)
+			}
+		})
+	}
+}
+
+func TestRegistry_Unmarshal_Unknown(t *testing.T) {
+	underTest := outbox.NewRegistry()
+
+	if _, err := underTest.Unmarshal("unknown", []byte("{}")); err == nil {
+		t.Fatalf("expected error mst be raised")
+	}
+}
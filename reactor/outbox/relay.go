@@ -0,0 +1,65 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"go.zenithar.org/pkg/log"
+	"go.zenithar.org/pkg/reactor"
+
+	"github.com/oklog/run"
+	"go.uber.org/zap"
+)
+
+// Relay registers an outbox relay actor. It polls the store every interval
+// and dispatches pending events to the given reactor. An event is marked as
+// dispatched only when the reactor handled it without error, so handlers must
+// be idempotent (at-least-once delivery).
+func Relay(store Store, bus reactor.Reactor, interval time.Duration, batchSize uint64) func(context.Context, *run.Group) {
+	return func(ctx context.Context, group *run.Group) {
+		ctx, cancel := context.WithCancel(ctx)
+
+		// Register relay actor
+		group.Add(
+			func() error {
+				log.For(ctx).Info("Starting outbox relay", zap.Duration("interval", interval))
+
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+
+				for {
+					select {
+					case <-ctx.Done():
+						return nil
+					case <-ticker.C:
+						drain(ctx, store, bus, batchSize)
+					}
+				}
+			},
+			func(e error) {
+				log.For(ctx).Info("Shutting outbox relay down")
+				cancel()
+			},
+		)
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func drain(ctx context.Context, store Store, bus reactor.Reactor, batchSize uint64) {
+	for {
+		count, err := store.Dispatch(ctx, batchSize, func(ctx context.Context, evt interface{}) error {
+			_, err := bus.Do(ctx, evt)
+			return err
+		})
+		if err != nil {
+			log.For(ctx).Error("Unable to dispatch outbox events", zap.Error(err))
+			return
+		}
+
+		// Continue while batches are full
+		if uint64(count) < batchSize || ctx.Err() != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package outbox_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jmoiron/sqlx"
+	"github.com/oklog/run"
+
+	"go.zenithar.org/pkg/reactor/outbox"
+	"go.zenithar.org/pkg/reactor/reactortest"
+)
+
+// memoryStore dispatches queued events, failed ones are kept queued.
+type memoryStore struct {
+	sync.Mutex
+	pending []interface{}
+	calls   int
+}
+
+func (s *memoryStore) Publish(ctx context.Context, exec sqlx.ExecerContext, events ...interface{}) error {
+	s.Lock()
+	s.pending = append(s.pending, events...)
+	s.Unlock()
+	return nil
+}
+
+func (s *memoryStore) Dispatch(ctx context.Context, batchSize uint64, fn outbox.HandlerFunc) (int, error) {
+	s.Lock()
+	defer s.Unlock()
+	s.calls++
+
+	count := 0
+	var failed []interface{}
+	for len(s.pending) > 0 && uint64(count+len(failed)) < batchSize {
+		evt := s.pending[0]
+		s.pending = s.pending[1:]
+		if err := fn(ctx, evt); err != nil {
+			failed = append(failed, evt)
+			continue
+		}
+		count++
+	}
+	s.pending = append(s.pending, failed...)
+
+	return count, nil
+}
+
+func (s *memoryStore) Pending() int {
+	s.Lock()
+	defer s.Unlock()
+	return len(s.pending)
+}
+
+func TestRelay(t *testing.T) {
+	store := &memoryStore{}
+	bus := reactortest.New("test")
+	bus.Stub(userCreated{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		if req.(userCreated).ID == "ko" {
+			return nil, fmt.Errorf("foo")
+		}
+		return nil, nil
+	})
+
+	// Publish more events than a batch
+	if err := store.Publish(context.Background(), nil, userCreated{ID: "1"}, userCreated{ID: "ko"}, userCreated{ID: "2"}, userCreated{ID: "3"}); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+
+	// Run the relay until all dispatchable events are handled
+	var group run.Group
+	outbox.Relay(store, bus, 10*time.Millisecond, 2)(context.Background(), &group)
+
+	done := make(chan struct{})
+	group.Add(func() error {
+		deadline := time.After(time.Second)
+		for store.Pending() > 1 {
+			select {
+			case <-deadline:
+				return fmt.Errorf("events have not been dispatched")
+			case <-time.After(5 * time.Millisecond):
+			}
+		}
+		return nil
+	}, func(error) {})
+	go func() {
+		if err := group.Run(); err != nil {
+			t.Errorf("error must not be raised, got %v", err)
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("relay must stop when the group is interrupted")
+	}
+
+	// Failed events are kept for a later dispatch
+	if diff := cmp.Diff([]interface{}{userCreated{ID: "1"}, userCreated{ID: "ko"}, userCreated{ID: "2"}, userCreated{ID: "3"}}, bus.Done()[:4]); diff != "" {
+		t.Errorf("%s", diff)
+	}
+	if store.Pending() != 1 {
+		t.Errorf("failed event must be kept pending")
+	}
+}
@@ -0,0 +1,99 @@
+package reactortest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.zenithar.org/pkg/reactor"
+)
+
+// Result holds a callback invocation.
+type Result struct {
+	Context  context.Context
+	Response interface{}
+	Err      error
+}
+
+// Callback records asynchronous callback invocations, the recording is
+// unbounded so invocations never block.
+type Callback struct {
+	locker  sync.Mutex
+	results []Result
+	notify  chan struct{}
+}
+
+// NewCallback returns a callback recorder.
+func NewCallback() *Callback {
+	return &Callback{
+		notify: make(chan struct{}, 1),
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// Func returns the reactor callback to pass to Send.
+func (c *Callback) Func() reactor.Callback {
+	return func(ctx context.Context, res interface{}, err error) {
+		c.locker.Lock()
+		c.results = append(c.results, Result{
+			Context:  ctx,
+			Response: res,
+			Err:      err,
+		})
+		c.locker.Unlock()
+
+		// Wake up waiter
+		select {
+		case c.notify <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Wait for the next callback invocation, the test fails if none occurs
+// before the timeout.
+func (c *Callback) Wait(t testing.TB, timeout time.Duration) Result {
+	t.Helper()
+
+	deadline := time.After(timeout)
+	for {
+		if res, ok := c.next(); ok {
+			return res
+		}
+
+		select {
+		case <-c.notify:
+		case <-deadline:
+			t.Fatalf("callback has not been called within %v", timeout)
+			return Result{}
+		}
+	}
+}
+
+// AssertNotCalled checks that no callback invocation occurs during the given
+// duration.
+func (c *Callback) AssertNotCalled(t testing.TB, d time.Duration) {
+	t.Helper()
+
+	time.Sleep(d)
+	if res, ok := c.next(); ok {
+		t.Fatalf("callback must not be called, got (%v, %v)", res.Response, res.Err)
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func (c *Callback) next() (Result, bool) {
+	c.locker.Lock()
+	defer c.locker.Unlock()
+
+	if len(c.results) == 0 {
+		return Result{}, false
+	}
+
+	res := c.results[0]
+	c.results = c.results[1:]
+	return res, true
+}
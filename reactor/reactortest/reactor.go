@@ -0,0 +1,134 @@
+package reactortest
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+
+	"go.zenithar.org/pkg/reactor"
+)
+
+// Reactor is a recording reactor implementation for tests. It records all
+// sent and done messages, and dispatches them to stubs first, then to
+// registered handlers. Stubs are dispatched by a default reactor, so they
+// receive the same message metadata as registered handlers.
+type Reactor struct {
+	delegate reactor.Reactor
+	stubs    reactor.Reactor
+
+	locker  sync.Mutex
+	stubbed map[reflect.Type]bool
+	sent    []interface{}
+	done    []interface{}
+}
+
+// New returns a recording reactor instance.
+func New(name string) *Reactor {
+	return &Reactor{
+		delegate: reactor.New(name),
+		stubs:    reactor.New(name),
+		stubbed:  map[reflect.Type]bool{},
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// Stub overrides the handler used for the given message type.
+func (r *Reactor) Stub(msg interface{}, fn reactor.HandlerFunc) {
+	r.locker.Lock()
+	r.stubs.RegisterHandler(msg, fn)
+	r.stubbed[reflect.TypeOf(msg)] = true
+	r.locker.Unlock()
+}
+
+// Sent returns messages received as asynchronous calls.
+func (r *Reactor) Sent() []interface{} {
+	r.locker.Lock()
+	defer r.locker.Unlock()
+	return append([]interface{}(nil), r.sent...)
+}
+
+// Done returns messages received as synchronous calls.
+func (r *Reactor) Done() []interface{} {
+	r.locker.Lock()
+	defer r.locker.Unlock()
+	return append([]interface{}(nil), r.done...)
+}
+
+// Reset clears recorded messages.
+func (r *Reactor) Reset() {
+	r.locker.Lock()
+	r.sent, r.done = nil, nil
+	r.locker.Unlock()
+}
+
+// -----------------------------------------------------------------------------
+
+// Send records the message and dispatches it as an asynchronous call.
+func (r *Reactor) Send(ctx context.Context, req interface{}, cb reactor.Callback) error {
+	r.locker.Lock()
+	r.sent = append(r.sent, req)
+	stubbed := r.stubbed[reflect.TypeOf(req)]
+	r.locker.Unlock()
+
+	// Stubbed message
+	if stubbed {
+		return r.stubs.Send(ctx, req, cb)
+	}
+
+	// Delegate to registered handlers
+	return r.delegate.Send(ctx, req, cb)
+}
+
+// Do records the message and dispatches it as a synchronous call.
+func (r *Reactor) Do(ctx context.Context, req interface{}) (interface{}, error) {
+	r.locker.Lock()
+	r.done = append(r.done, req)
+	stubbed := r.stubbed[reflect.TypeOf(req)]
+	r.locker.Unlock()
+
+	// Stubbed message
+	if stubbed {
+		return r.stubs.Do(ctx, req)
+	}
+
+	// Delegate to registered handlers
+	return r.delegate.Do(ctx, req)
+}
+
+// RegisterHandler registers a message type handler.
+func (r *Reactor) RegisterHandler(msg interface{}, fn reactor.Handler) {
+	r.delegate.RegisterHandler(msg, fn)
+}
+
+// Registrations returns registered handlers sorted by message type, stubs
+// replace the handler registered for the same message type.
+func (r *Reactor) Registrations() []reactor.Registration {
+	stubs := r.stubs.(reactor.Introspector).Registrations()
+
+	// Skip overridden handlers
+	var res []reactor.Registration
+	if i, ok := r.delegate.(reactor.Introspector); ok {
+		for _, reg := range i.Registrations() {
+			overridden := false
+			for _, stub := range stubs {
+				if stub.MessageType == reg.MessageType {
+					overridden = true
+					break
+				}
+			}
+			if !overridden {
+				res = append(res, reg)
+			}
+		}
+	}
+	res = append(res, stubs...)
+
+	// Sort by message type
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].MessageType < res[j].MessageType
+	})
+
+	return res
+}
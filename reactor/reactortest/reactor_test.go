@@ -0,0 +1,143 @@
+package reactortest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.zenithar.org/pkg/reactor"
+	"go.zenithar.org/pkg/reactor/reactortest"
+	"go.zenithar.org/pkg/types"
+)
+
+type ping struct{ ID int }
+type pong struct{ ID int }
+
+func TestReactor_Stub(t *testing.T) {
+	ctx := context.Background()
+
+	// Reactor
+	underTest := reactortest.New("test")
+	underTest.RegisterHandler(&ping{}, reactor.HandlerFunc(func(_ context.Context, req interface{}) (interface{}, error) {
+		return &pong{ID: req.(*ping).ID}, nil
+	}))
+
+	// Registered handler
+	got, err := underTest.Do(ctx, &ping{ID: 1})
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if want := (&pong{ID: 1}); !cmp.Equal(got, want) {
+		t.Fatalf("got %v, wanted %v", got, want)
+	}
+
+	// Stubbed handler
+	underTest.Stub(&ping{}, func(_ context.Context, req interface{}) (interface{}, error) {
+		return &pong{ID: 42}, nil
+	})
+
+	cb := reactortest.NewCallback()
+	if err := underTest.Send(ctx, &ping{ID: 2}, cb.Func()); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if res := cb.Wait(t, time.Second); !cmp.Equal(res.Response, &pong{ID: 42}) {
+		t.Fatalf("got %v, wanted %v", res.Response, &pong{ID: 42})
+	}
+
+	// Recorded messages
+	if want := []interface{}{&ping{ID: 1}}; !cmp.Equal(underTest.Done(), want) {
+		t.Fatalf("got %v, wanted %v", underTest.Done(), want)
+	}
+	if want := []interface{}{&ping{ID: 2}}; !cmp.Equal(underTest.Sent(), want) {
+		t.Fatalf("got %v, wanted %v", underTest.Sent(), want)
+	}
+
+	underTest.Reset()
+	if len(underTest.Done()) != 0 || len(underTest.Sent()) != 0 {
+		t.Fatalf("recorded messages must be cleared")
+	}
+}
+
+func TestReactor_Unknown(t *testing.T) {
+	ctx := context.Background()
+
+	// Reactor
+	underTest := reactortest.New("test")
+
+	cb := reactortest.NewCallback()
+	if err := underTest.Send(ctx, &ping{}, cb.Func()); err == nil {
+		t.Fatalf("expected error mst be raised")
+	}
+	cb.AssertNotCalled(t, 10*time.Millisecond)
+
+	if want := []interface{}{&ping{}}; !cmp.Equal(underTest.Sent(), want) {
+		t.Fatalf("got %v, wanted %v", underTest.Sent(), want)
+	}
+}
+
+func TestReactor_StubMetadata(t *testing.T) {
+	ctx := types.WithCorrelationID(context.Background(), "corr-1")
+	ctx = types.WithMessageID(ctx, "parent")
+
+	// Reactor
+	underTest := reactortest.New("test")
+	underTest.Stub(&ping{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return types.MetadataFromContext(ctx), nil
+	})
+
+	got, err := underTest.Do(ctx, &ping{})
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+
+	// Same propagation as registered handlers
+	md := got.(types.Metadata)
+	if md.First(types.MetadataCorrelationID) != "corr-1" || md.First(types.MetadataCausationID) != "parent" {
+		t.Fatalf("metadata must be propagated, got %v", md)
+	}
+	if id := md.First(types.MetadataMessageID); id == "" || id == "parent" {
+		t.Fatalf("a new message identifier must be generated, got %q", id)
+	}
+}
+
+func TestReactor_Registrations(t *testing.T) {
+	handler := reactor.HandlerFunc(func(_ context.Context, req interface{}) (interface{}, error) {
+		return req, nil
+	})
+
+	// Reactor
+	underTest := reactortest.New("test")
+	underTest.RegisterHandler(&ping{}, reactor.Named("ping-handler", handler))
+	underTest.RegisterHandler(&pong{}, reactor.Named("pong-handler", handler))
+	underTest.Stub(&ping{}, handler)
+
+	got := underTest.Registrations()
+	if len(got) != 2 {
+		t.Fatalf("got %v, wanted one registration per message type", got)
+	}
+	if got[0].MessageType != "*reactortest_test.ping" || got[0].HandlerName == "ping-handler" {
+		t.Fatalf("stub must replace the registered handler, got %v", got[0])
+	}
+	if want := (reactor.Registration{MessageType: "*reactortest_test.pong", HandlerName: "pong-handler"}); got[1] != want {
+		t.Fatalf("got %v, wanted %v", got[1], want)
+	}
+}
+
+func TestCallback_Unbounded(t *testing.T) {
+	cb := reactortest.NewCallback()
+
+	// Invocations must never block
+	fn := cb.Func()
+	for i := 0; i < 1000; i++ {
+		fn(context.Background(), i, nil)
+	}
+
+	for i := 0; i < 1000; i++ {
+		if res := cb.Wait(t, time.Second); res.Response != i {
+			t.Fatalf("got %v, wanted %v", res.Response, i)
+		}
+	}
+	cb.AssertNotCalled(t, time.Millisecond)
+}
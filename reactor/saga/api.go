@@ -0,0 +1,117 @@
+package saga
+
+import (
+	"context"
+
+	"go.zenithar.org/pkg/errors"
+)
+
+// Status is the enumeration for saga execution status
+type Status int
+
+const (
+	// Running means steps are being executed
+	Running Status = iota + 1
+	// Completed means all steps have been executed successfully
+	Completed
+	// Compensating means a step failed and compensations are being executed
+	Compensating
+	// Compensated means all executed steps have been compensated
+	Compensated
+	// Failed means a compensation failed, manual intervention is required
+	Failed
+)
+
+var statuses = [...]string{
+	"running",
+	"completed",
+	"compensating",
+	"compensated",
+	"failed",
+}
+
+func (s Status) String() string {
+	if s < Running || int(s) > len(statuses) {
+		return "unknown"
+	}
+	return statuses[s-1]
+}
+
+// -----------------------------------------------------------------------------
+
+// CommandFunc builds a reactor command from the saga state.
+type CommandFunc func(ctx context.Context, state *State) (interface{}, error)
+
+// Step describes a saga step as a command and its compensating command.
+type Step struct {
+	// Name of the step, used as key for result storage in saga data.
+	Name string
+	// Command builds the command to execute.
+	Command CommandFunc
+	// Compensation builds the command used to revert the step, optional.
+	Compensation CommandFunc
+}
+
+// Definition declares a saga as an ordered list of steps.
+type Definition struct {
+	Name  string
+	Steps []Step
+}
+
+// NewDefinition returns a checked saga definition.
+func NewDefinition(name string, steps ...Step) (*Definition, error) {
+	def := &Definition{
+		Name:  name,
+		Steps: steps,
+	}
+	if err := def.Validate(); err != nil {
+		return nil, err
+	}
+
+	return def, nil
+}
+
+// Validate checks that the definition is named and all steps have a unique
+// name and a command.
+func (d *Definition) Validate() error {
+	if d.Name == "" {
+		return errors.Newf(errors.InvalidArgument, nil, "saga: name must not be blank")
+	}
+
+	names := map[string]bool{}
+	for i, step := range d.Steps {
+		if step.Name == "" {
+			return errors.Newf(errors.InvalidArgument, nil, "saga(%s): step #%d name must not be blank", d.Name, i)
+		}
+		if names[step.Name] {
+			return errors.Newf(errors.InvalidArgument, nil, "saga(%s): step '%s' is declared twice", d.Name, step.Name)
+		}
+		if step.Command == nil {
+			return errors.Newf(errors.InvalidArgument, nil, "saga(%s): step '%s' command must not be nil", d.Name, step.Name)
+		}
+		names[step.Name] = true
+	}
+
+	return nil
+}
+
+// State holds the persisted saga execution state.
+type State struct {
+	ID        string
+	Name      string
+	Status    Status
+	Completed int
+	Data      map[string]interface{}
+	Error     string
+}
+
+// Store describes saga state persistence contract.
+type Store interface {
+	// Create the given state, an AlreadyExists error is returned if a state
+	// with the same identifier exists.
+	Create(ctx context.Context, state *State) error
+	// Save the given state.
+	Save(ctx context.Context, state *State) error
+	// Get the state matching the given identifier.
+	Get(ctx context.Context, id string) (*State, error)
+}
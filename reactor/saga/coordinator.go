@@ -0,0 +1,170 @@
+package saga
+
+import (
+	"context"
+	"fmt"
+
+	"go.zenithar.org/pkg/errors"
+	"go.zenithar.org/pkg/log"
+	"go.zenithar.org/pkg/reactor"
+
+	"go.uber.org/zap"
+)
+
+// Coordinator executes saga definitions using a reactor.
+type Coordinator struct {
+	bus   reactor.Reactor
+	store Store
+}
+
+// NewCoordinator returns a saga coordinator instance.
+func NewCoordinator(bus reactor.Reactor, store Store) *Coordinator {
+	return &Coordinator{
+		bus:   bus,
+		store: store,
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// Start a new saga execution identified by id, with the given initial data.
+func (c *Coordinator) Start(ctx context.Context, def *Definition, id string, data map[string]interface{}) (*State, error) {
+	// Check arguments
+	if def == nil {
+		return nil, errors.Newf(errors.InvalidArgument, nil, "saga: definition must not be nil")
+	}
+	if err := def.Validate(); err != nil {
+		return nil, err
+	}
+	if id == "" {
+		return nil, errors.Newf(errors.InvalidArgument, nil, "saga(%s): identifier must not be blank", def.Name)
+	}
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+
+	// Initialize state
+	state := &State{
+		ID:     id,
+		Name:   def.Name,
+		Status: Running,
+		Data:   data,
+	}
+	if err := c.store.Create(ctx, state); err != nil {
+		return nil, fmt.Errorf("saga(%s): unable to create state '%s': %w", def.Name, id, err)
+	}
+
+	return state, c.run(ctx, def, state)
+}
+
+// Resume a persisted saga execution, used to finish sagas interrupted by a
+// process failure.
+func (c *Coordinator) Resume(ctx context.Context, def *Definition, id string) (*State, error) {
+	// Check arguments
+	if def == nil {
+		return nil, errors.Newf(errors.InvalidArgument, nil, "saga: definition must not be nil")
+	}
+	if err := def.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Retrieve state
+	state, err := c.store.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("saga(%s): unable to retrieve state '%s': %w", def.Name, id, err)
+	}
+	if state.Name != def.Name {
+		return nil, errors.Newf(errors.FailedPrecondition, nil, "saga(%s): state '%s' belongs to saga '%s'", def.Name, id, state.Name)
+	}
+
+	return state, c.run(ctx, def, state)
+}
+
+// -----------------------------------------------------------------------------
+
+func (c *Coordinator) run(ctx context.Context, def *Definition, state *State) error {
+	switch state.Status {
+	case Running:
+		// Execute remaining steps
+		for state.Completed < len(def.Steps) {
+			step := def.Steps[state.Completed]
+
+			res, err := c.execute(ctx, state, step.Command)
+			if err != nil {
+				log.For(ctx).Warn("Saga step failed, compensating", zap.String("saga", def.Name), zap.String("id", state.ID), zap.String("step", step.Name), zap.Error(err))
+
+				state.Status = Compensating
+				state.Error = err.Error()
+				if errSave := c.save(ctx, state); errSave != nil {
+					return errSave
+				}
+
+				if errComp := c.compensate(ctx, def, state); errComp != nil {
+					return errComp
+				}
+
+				return errors.Newf(errors.Aborted, err, "saga(%s): step '%s' failed, execution compensated", def.Name, step.Name)
+			}
+
+			// Keep step result
+			state.Data[step.Name] = res
+			state.Completed++
+			if err := c.save(ctx, state); err != nil {
+				return err
+			}
+		}
+
+		state.Status = Completed
+		return c.save(ctx, state)
+	case Compensating:
+		return c.compensate(ctx, def, state)
+	default:
+		// Nothing to do
+		return nil
+	}
+}
+
+func (c *Coordinator) compensate(ctx context.Context, def *Definition, state *State) error {
+	// Revert completed steps in reverse order
+	for state.Completed > 0 {
+		step := def.Steps[state.Completed-1]
+
+		if step.Compensation != nil {
+			if _, err := c.execute(ctx, state, step.Compensation); err != nil {
+				state.Status = Failed
+				state.Error = err.Error()
+				if errSave := c.save(ctx, state); errSave != nil {
+					return errSave
+				}
+
+				return errors.Newf(errors.Internal, err, "saga(%s): unable to compensate step '%s'", def.Name, step.Name)
+			}
+		}
+
+		state.Completed--
+		if err := c.save(ctx, state); err != nil {
+			return err
+		}
+	}
+
+	state.Status = Compensated
+	return c.save(ctx, state)
+}
+
+func (c *Coordinator) execute(ctx context.Context, state *State, fn CommandFunc) (interface{}, error) {
+	// Build command
+	cmd, err := fn(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+
+	// Delegate to reactor
+	return c.bus.Do(ctx, cmd)
+}
+
+func (c *Coordinator) save(ctx context.Context, state *State) error {
+	if err := c.store.Save(ctx, state); err != nil {
+		return fmt.Errorf("saga(%s): unable to save state '%s': %w", state.Name, state.ID, err)
+	}
+	return nil
+}
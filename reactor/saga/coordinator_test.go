@@ -0,0 +1,254 @@
+package saga_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/xerrors"
+
+	"go.zenithar.org/pkg/errors"
+	"go.zenithar.org/pkg/reactor"
+	"go.zenithar.org/pkg/reactor/saga"
+)
+
+type reserveStock struct{ Order string }
+type releaseStock struct{ Order string }
+type chargePayment struct{ Order string }
+type refundPayment struct{ Order string }
+type shipOrder struct{ Order string }
+
+type journal struct {
+	sync.Mutex
+	entries []string
+}
+
+func (j *journal) handler(name string, err error) reactor.Handler {
+	return reactor.HandlerFunc(func(_ context.Context, req interface{}) (interface{}, error) {
+		j.Lock()
+		j.entries = append(j.entries, name)
+		j.Unlock()
+		return name, err
+	})
+}
+
+func command(fn func(order string) interface{}) saga.CommandFunc {
+	return func(_ context.Context, state *saga.State) (interface{}, error) {
+		return fn(state.Data["order"].(string)), nil
+	}
+}
+
+func fulfilment() *saga.Definition {
+	return &saga.Definition{
+		Name: "fulfilment",
+		Steps: []saga.Step{
+			{
+				Name:         "stock",
+				Command:      command(func(o string) interface{} { return &reserveStock{Order: o} }),
+				Compensation: command(func(o string) interface{} { return &releaseStock{Order: o} }),
+			},
+			{
+				Name:         "payment",
+				Command:      command(func(o string) interface{} { return &chargePayment{Order: o} }),
+				Compensation: command(func(o string) interface{} { return &refundPayment{Order: o} }),
+			},
+			{
+				Name:    "shipping",
+				Command: command(func(o string) interface{} { return &shipOrder{Order: o} }),
+			},
+		},
+	}
+}
+
+func TestCoordinator_Start(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		shipErr     error
+		refundErr   error
+		wantErr     bool
+		wantStatus  saga.Status
+		wantJournal []string
+	}{
+		{
+			name:        "all steps succeed",
+			wantStatus:  saga.Completed,
+			wantJournal: []string{"reserve", "charge", "ship"},
+		},
+		{
+			name:        "last step fails",
+			shipErr:     fmt.Errorf("carrier unavailable"),
+			wantErr:     true,
+			wantStatus:  saga.Compensated,
+			wantJournal: []string{"reserve", "charge", "ship", "refund", "release"},
+		},
+		{
+			name:        "compensation fails",
+			shipErr:     fmt.Errorf("carrier unavailable"),
+			refundErr:   fmt.Errorf("bank unavailable"),
+			wantErr:     true,
+			wantStatus:  saga.Failed,
+			wantJournal: []string{"reserve", "charge", "ship", "refund"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Default instances
+			ctx := context.Background()
+			j := &journal{}
+			store := saga.InMemory()
+
+			// Reactor
+			bus := reactor.New(tt.name)
+			bus.RegisterHandler(&reserveStock{}, j.handler("reserve", nil))
+			bus.RegisterHandler(&releaseStock{}, j.handler("release", nil))
+			bus.RegisterHandler(&chargePayment{}, j.handler("charge", nil))
+			bus.RegisterHandler(&refundPayment{}, j.handler("refund", tt.refundErr))
+			bus.RegisterHandler(&shipOrder{}, j.handler("ship", tt.shipErr))
+
+			// Coordinator
+			underTest := saga.NewCoordinator(bus, store)
+
+			// Call operation
+			state, err := underTest.Start(ctx, fulfilment(), "saga-1", map[string]interface{}{"order": "order-1"})
+			if tt.wantErr && err == nil {
+				t.Fatalf("expected error mst be raised")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("error must not be raised, got %v", err)
+			}
+			if state.Status != tt.wantStatus {
+				t.Fatalf("got status %v, wanted %v", state.Status, tt.wantStatus)
+			}
+			if !cmp.Equal(j.entries, tt.wantJournal) {
+				t.Fatalf("got %v, wanted %v", j.entries, tt.wantJournal)
+			}
+
+			// Check persisted state
+			saved, err := store.Get(ctx, "saga-1")
+			if err != nil {
+				t.Fatalf("error must not be raised, got %v", err)
+			}
+			if !cmp.Equal(saved, state) {
+				t.Fatalf("got %v, wanted %v", saved, state)
+			}
+		})
+	}
+}
+
+func TestCoordinator_Resume(t *testing.T) {
+	ctx := context.Background()
+	j := &journal{}
+	store := saga.InMemory()
+
+	// Reactor
+	bus := reactor.New("resume")
+	bus.RegisterHandler(&releaseStock{}, j.handler("release", nil))
+	bus.RegisterHandler(&refundPayment{}, j.handler("refund", nil))
+
+	// Simulate an interrupted compensation
+	if err := store.Save(ctx, &saga.State{
+		ID:        "saga-1",
+		Name:      "fulfilment",
+		Status:    saga.Compensating,
+		Completed: 2,
+		Data:      map[string]interface{}{"order": "order-1"},
+	}); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+
+	// Coordinator
+	underTest := saga.NewCoordinator(bus, store)
+
+	state, err := underTest.Resume(ctx, fulfilment(), "saga-1")
+	if err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+	if state.Status != saga.Compensated {
+		t.Fatalf("got status %v, wanted %v", state.Status, saga.Compensated)
+	}
+	if want := []string{"refund", "release"}; !cmp.Equal(j.entries, want) {
+		t.Fatalf("got %v, wanted %v", j.entries, want)
+	}
+}
+
+func TestCoordinator_Start_AlreadyExists(t *testing.T) {
+	ctx := context.Background()
+	j := &journal{}
+
+	// Reactor
+	bus := reactor.New("exists")
+	bus.RegisterHandler(&reserveStock{}, j.handler("reserve", nil))
+	bus.RegisterHandler(&chargePayment{}, j.handler("charge", nil))
+	bus.RegisterHandler(&shipOrder{}, j.handler("ship", nil))
+
+	// Coordinator
+	underTest := saga.NewCoordinator(bus, saga.InMemory())
+
+	if _, err := underTest.Start(ctx, fulfilment(), "saga-1", map[string]interface{}{"order": "order-1"}); err != nil {
+		t.Fatalf("error must not be raised, got %v", err)
+	}
+
+	// Same identifier
+	_, err := underTest.Start(ctx, fulfilment(), "saga-1", map[string]interface{}{"order": "order-2"})
+	var e *errors.Error
+	if !xerrors.As(err, &e) || e.Code != errors.AlreadyExists {
+		t.Fatalf("expected already exists error, got %v", err)
+	}
+	if want := []string{"reserve", "charge", "ship"}; !cmp.Equal(j.entries, want) {
+		t.Fatalf("got %v, wanted %v", j.entries, want)
+	}
+}
+
+func TestDefinition_Validate(t *testing.T) {
+	noop := command(func(o string) interface{} { return &shipOrder{Order: o} })
+
+	testCases := []struct {
+		name    string
+		def     string
+		steps   []saga.Step
+		wantErr bool
+	}{
+		{name: "valid", def: "valid", steps: []saga.Step{{Name: "ship", Command: noop}}},
+		{name: "blank name", steps: []saga.Step{{Name: "ship", Command: noop}}, wantErr: true},
+		{name: "blank step name", def: "invalid", steps: []saga.Step{{Command: noop}}, wantErr: true},
+		{name: "duplicate step", def: "invalid", steps: []saga.Step{{Name: "ship", Command: noop}, {Name: "ship", Command: noop}}, wantErr: true},
+		{name: "nil command", def: "invalid", steps: []saga.Step{{Name: "ship"}}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := saga.NewDefinition(tt.def, tt.steps...)
+			if tt.wantErr && err == nil {
+				t.Fatalf("expected error mst be raised")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("error must not be raised, got %v", err)
+			}
+		})
+	}
+
+	// Literal definitions are checked on start
+	underTest := saga.NewCoordinator(reactor.New("invalid"), saga.InMemory())
+	if _, err := underTest.Start(context.Background(), &saga.Definition{Name: "invalid", Steps: []saga.Step{{Name: "ship"}}}, "saga-1", nil); err == nil {
+		t.Fatalf("expected error mst be raised")
+	}
+}
+
+func TestStatus_String(t *testing.T) {
+	if got := saga.Status(0).String(); got != "unknown" {
+		t.Fatalf("got %q, wanted %q", got, "unknown")
+	}
+	if got := saga.Compensated.String(); got != "compensated" {
+		t.Fatalf("got %q, wanted %q", got, "compensated")
+	}
+}
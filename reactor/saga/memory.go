@@ -0,0 +1,64 @@
+package saga
+
+import (
+	"context"
+	"sync"
+
+	"go.zenithar.org/pkg/errors"
+)
+
+type memoryStore struct {
+	locker sync.RWMutex
+	states map[string]State
+}
+
+// InMemory returns a volatile saga state store.
+func InMemory() Store {
+	return &memoryStore{
+		states: map[string]State{},
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+func (s *memoryStore) Create(_ context.Context, state *State) error {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	if _, ok := s.states[state.ID]; ok {
+		return errors.Newf(errors.AlreadyExists, nil, "saga: state '%s' already exists", state.ID)
+	}
+	s.states[state.ID] = copyState(state)
+
+	return nil
+}
+
+func (s *memoryStore) Save(_ context.Context, state *State) error {
+	s.locker.Lock()
+	s.states[state.ID] = copyState(state)
+	s.locker.Unlock()
+	return nil
+}
+
+func (s *memoryStore) Get(_ context.Context, id string) (*State, error) {
+	s.locker.RLock()
+	state, ok := s.states[id]
+	s.locker.RUnlock()
+	if !ok {
+		return nil, errors.Newf(errors.NotFound, nil, "saga: state '%s' not found", id)
+	}
+
+	res := copyState(&state)
+	return &res, nil
+}
+
+// -----------------------------------------------------------------------------
+
+func copyState(state *State) State {
+	res := *state
+	res.Data = make(map[string]interface{}, len(state.Data))
+	for k, v := range state.Data {
+		res.Data[k] = v
+	}
+	return res
+}
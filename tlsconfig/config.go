@@ -17,27 +17,27 @@ import (
 
 // Options represents the information needed to create client and server TLS configurations.
 type Options struct {
-	CAFile string
+	CAFile string `toml:"caFile" comment:"Certificate authority bundle file"`
 
 	// If either CertFile or KeyFile is empty, Client() will not load them
 	// preventing the client from authenticating to the server.
 	// However, Server() requires them and will error out if they are empty.
-	CertFile string
-	KeyFile  string
+	CertFile string `toml:"certFile" comment:"Certificate file"`
+	KeyFile  string `toml:"keyFile" comment:"Private key file"`
 
 	// client-only option
-	InsecureSkipVerify bool
+	InsecureSkipVerify bool `toml:"insecureSkipVerify" default:"false" comment:"Disable peer certificate and host name verification"`
 	// If ExclusiveRootPools is set, then if a CA file is provided, the root pool used for TLS
 	// creds will include exclusively the roots in that CA file.  If no CA file is provided,
 	// the system pool will be used.
-	ExclusiveRootPools bool
-	MinVersion         uint16
+	ExclusiveRootPools bool   `toml:"exclusiveRootPools" default:"false" comment:"Use only the CA bundle as trusted roots"`
+	MinVersion         uint16 `toml:"minVersion" comment:"Minimum TLS version (0x0303 for TLS1.2, 0x0304 for TLS1.3)"`
 	// If Passphrase is set, it will be used to decrypt a TLS private key
 	// if the key is encrypted
-	Passphrase string
+	Passphrase string `toml:"passphrase" comment:"Private key passphrase"`
 
 	// server-only option
-	ClientAuth tls.ClientAuthType
+	ClientAuth tls.ClientAuthType `toml:"clientAuth" comment:"Client certificate policy (0: none, 1: request, 2: require any, 3: verify if given, 4: require and verify)"`
 }
 
 // Extra (server-side) accepted CBC cipher suites - will phase out in the future
@@ -0,0 +1,105 @@
+package types
+
+import "context"
+
+type contextKey string
+
+const (
+	metadataContextKey = contextKey("metadata")
+)
+
+const (
+	// MetadataCorrelationID is the metadata key of the identifier shared by all
+	// messages of the same flow.
+	MetadataCorrelationID = "correlation-id"
+	// MetadataCausationID is the metadata key of the identifier of the message
+	// which caused the current one.
+	MetadataCausationID = "causation-id"
+	// MetadataMessageID is the metadata key of the current message identifier.
+	MetadataMessageID = "message-id"
+	// MetadataTenant is the metadata key of the tenant identifier.
+	MetadataTenant = "tenant"
+	// MetadataActor is the metadata key of the acting principal identity.
+	MetadataActor = "actor"
+)
+
+// WithMetadata returns a copy of ctx holding a copy of the given metadata.
+func WithMetadata(ctx context.Context, md Metadata) context.Context {
+	return context.WithValue(ctx, metadataContextKey, md.Copy())
+}
+
+// MetadataFromContext returns a copy of the metadata attached to ctx, or an
+// empty metadata.
+func MetadataFromContext(ctx context.Context) Metadata {
+	if md, ok := ctx.Value(metadataContextKey).(Metadata); ok {
+		return md.Copy()
+	}
+	return Metadata{}
+}
+
+// -----------------------------------------------------------------------------
+
+// WithCorrelationID returns a copy of ctx with the given correlation identifier.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return withValue(ctx, MetadataCorrelationID, id)
+}
+
+// CorrelationID returns the correlation identifier attached to ctx.
+func CorrelationID(ctx context.Context) string {
+	return value(ctx, MetadataCorrelationID)
+}
+
+// WithCausationID returns a copy of ctx with the given causation identifier.
+func WithCausationID(ctx context.Context, id string) context.Context {
+	return withValue(ctx, MetadataCausationID, id)
+}
+
+// CausationID returns the causation identifier attached to ctx.
+func CausationID(ctx context.Context) string {
+	return value(ctx, MetadataCausationID)
+}
+
+// WithMessageID returns a copy of ctx with the given message identifier.
+func WithMessageID(ctx context.Context, id string) context.Context {
+	return withValue(ctx, MetadataMessageID, id)
+}
+
+// MessageID returns the message identifier attached to ctx.
+func MessageID(ctx context.Context) string {
+	return value(ctx, MetadataMessageID)
+}
+
+// WithTenant returns a copy of ctx with the given tenant identifier.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return withValue(ctx, MetadataTenant, tenant)
+}
+
+// Tenant returns the tenant identifier attached to ctx.
+func Tenant(ctx context.Context) string {
+	return value(ctx, MetadataTenant)
+}
+
+// WithActor returns a copy of ctx with the given acting principal identity.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return withValue(ctx, MetadataActor, actor)
+}
+
+// Actor returns the acting principal identity attached to ctx.
+func Actor(ctx context.Context) string {
+	return value(ctx, MetadataActor)
+}
+
+// -----------------------------------------------------------------------------
+
+func withValue(ctx context.Context, k, v string) context.Context {
+	md := MetadataFromContext(ctx)
+	md.Set(k, v)
+	return context.WithValue(ctx, metadataContextKey, md)
+}
+
+func value(ctx context.Context, k string) string {
+	if md, ok := ctx.Value(metadataContextKey).(Metadata); ok {
+		return md.First(k)
+	}
+	return ""
+}
@@ -0,0 +1,41 @@
+package types_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"go.zenithar.org/pkg/types"
+)
+
+func TestContextMetadata(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ctx := context.Background()
+	g.Expect(types.MetadataFromContext(ctx).Len()).To(Equal(0), "Metadata should be empty")
+	g.Expect(types.CorrelationID(ctx)).To(BeEmpty(), "Correlation ID should be empty")
+
+	ctx = types.WithCorrelationID(ctx, "correlation")
+	ctx = types.WithCausationID(ctx, "causation")
+	ctx = types.WithTenant(ctx, "tenant")
+	ctx = types.WithActor(ctx, "actor")
+	g.Expect(types.CorrelationID(ctx)).To(Equal("correlation"), "Correlation ID should be set")
+	g.Expect(types.CausationID(ctx)).To(Equal("causation"), "Causation ID should be set")
+	g.Expect(types.Tenant(ctx)).To(Equal("tenant"), "Tenant should be set")
+	g.Expect(types.Actor(ctx)).To(Equal("actor"), "Actor should be set")
+	g.Expect(types.MetadataFromContext(ctx).Len()).To(Equal(4), "Metadata should contain 4 keys")
+}
+
+func TestContextMetadataIsolation(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	parent := types.WithCorrelationID(context.Background(), "parent")
+	child := types.WithCorrelationID(parent, "child")
+	g.Expect(types.CorrelationID(parent)).To(Equal("parent"), "Parent context should not be modified")
+	g.Expect(types.CorrelationID(child)).To(Equal("child"), "Child context should be updated")
+
+	md := types.MetadataFromContext(parent)
+	md.Set(types.MetadataCorrelationID, "changed")
+	g.Expect(types.CorrelationID(parent)).To(Equal("parent"), "Context metadata should not be shared")
+}
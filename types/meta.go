@@ -40,3 +40,20 @@ func (md Metadata) Append(k string, vals ...string) {
 	k = strings.ToLower(k)
 	md[k] = append(md[k], vals...)
 }
+
+// Copy returns a copy of md.
+func (md Metadata) Copy() Metadata {
+	out := make(Metadata, len(md))
+	for k, v := range md {
+		out[k] = append([]string(nil), v...)
+	}
+	return out
+}
+
+// First returns the first value for a given key, or an empty string.
+func (md Metadata) First(k string) string {
+	if vals := md.Get(k); len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}